@@ -0,0 +1,332 @@
+// Package api serves the red days, notable days and business days of the
+// kal calendars as a JSON HTTP API
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xyproto/kal"
+)
+
+// The longest range of dates for the business-days endpoint
+const maxDays = 3660
+
+// The number of years that are kept in the cache of each calendar
+const cacheYears = 32
+
+// A Handler serves the JSON API, with these endpoints:
+//
+//	GET /v1/locales
+//	GET /v1/{locale}/holidays?year=2025&notable=true
+//	GET /v1/{locale}/day/2025-05-17
+//	GET /v1/{locale}/business-days?from=2025-01-01&to=2025-01-31
+//
+// The locale can also combine a language with the region of another, like
+// "en_NO". The names of the days are given in the first language in the
+// Accept-Language header that kal has names for, or else in the language
+// of the locale. The answers only change with new versions of kal, so
+// they can be cached by clients and proxies.
+type Handler struct {
+	calendars map[string]kal.Calendar
+	mut       sync.Mutex // held while finding or adding a calendar
+	mux       *http.ServeMux
+	MaxAge    time.Duration // how long the answers can be cached, one day if 0
+}
+
+// Create a new Handler, with a calendar for each locale that kal supports
+func NewHandler() *Handler {
+	h := &Handler{calendars: make(map[string]kal.Calendar), mux: http.NewServeMux()}
+	for _, locale := range kal.Locales() {
+		if cal, err := newCalendar(locale); err == nil {
+			h.calendars[locale] = cal
+		}
+	}
+	h.mux.HandleFunc("GET /v1/locales", h.locales)
+	h.mux.HandleFunc("GET /v1/{locale}/holidays", h.withCalendar(h.holidays))
+	h.mux.HandleFunc("GET /v1/{locale}/day/{date}", h.withCalendar(h.day))
+	h.mux.HandleFunc("GET /v1/{locale}/business-days", h.withCalendar(h.businessDays))
+	return h
+}
+
+// ServeHTTP serves the endpoints of the API
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// Write a JSON answer with caching headers, or 304 Not Modified if the
+// client already has it
+func (h *Handler) writeJSON(w http.ResponseWriter, r *http.Request, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	data = append(data, '\n')
+	hash := fnv.New64a()
+	hash.Write(data)
+	tag := fmt.Sprintf(`"%x"`, hash.Sum64())
+	maxAge := h.MaxAge
+	if maxAge <= 0 {
+		maxAge = 24 * time.Hour
+	}
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(maxAge/time.Second)))
+	w.Header().Set("Vary", "Accept-Language")
+	w.Header().Set("ETag", tag)
+	if match := r.Header.Get("If-None-Match"); match == tag || match == "*" {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Write(data)
+}
+
+// Write an error as JSON, that is not cached
+func writeError(w http.ResponseWriter, status int, message string) {
+	data, _ := json.Marshal(map[string]string{"error": message})
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	w.Write(append(data, '\n'))
+}
+
+// A request for a locale, with the calendar for the locale, with the names
+// in the language from Accept-Language
+type localeRequest struct {
+	locale string
+	cal    kal.Calendar
+}
+
+// Create a calendar for a locale, with a cache of at most cacheYears years
+func newCalendar(locale string) (kal.Calendar, error) {
+	cal, err := kal.NewCalendar(locale, false)
+	if err != nil {
+		return nil, err
+	}
+	return kal.NewBoundedCachedCalendar(cal, cacheYears), nil
+}
+
+// Find the calendar for a locale, like "nb_NO" or "en_NO", creating it on first use
+func (h *Handler) calendar(locale string) (kal.Calendar, bool) {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	if cal, ok := h.calendars[locale]; ok {
+		return cal, true
+	}
+	if match, err := kal.MatchLocale(locale); err != nil || match != locale {
+		return nil, false
+	}
+	cal, err := newCalendar(locale)
+	if err != nil {
+		return nil, false
+	}
+	h.calendars[locale] = cal
+	return cal, true
+}
+
+// Find the calendar for the locale in the path, before calling the given handler
+func (h *Handler) withCalendar(fn func(http.ResponseWriter, *http.Request, localeRequest)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		locale := r.PathValue("locale")
+		if _, ok := h.calendar(locale); !ok {
+			writeError(w, http.StatusNotFound, "unsupported locale: "+locale)
+			return
+		}
+		nameLocale := acceptLanguage(r.Header.Get("Accept-Language"), locale)
+		cal, ok := h.calendar(nameLocale)
+		if !ok {
+			nameLocale = locale
+			cal, _ = h.calendar(locale)
+		}
+		language, _, _ := strings.Cut(nameLocale, "_")
+		w.Header().Set("Content-Language", language)
+		fn(w, r, localeRequest{locale, cal})
+	}
+}
+
+// Find the locale for the names of the days, from an Accept-Language header
+// like "nb-NO,nb;q=0.9,en;q=0.8". The language of the given locale is
+// preferred if it is acceptable, else the first acceptable language that
+// kal has names for, combined with the region of the given locale, like
+// "en_NO". Returns the given locale if no language matches.
+func acceptLanguage(header, locale string) string {
+	type language struct {
+		tag string
+		q   float64
+	}
+	var languages []language
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if tag != "" && q > 0 {
+			languages = append(languages, language{tag, q})
+		}
+	}
+	sort.SliceStable(languages, func(i, j int) bool { return languages[i].q > languages[j].q })
+	lang, region, _ := strings.Cut(locale, "_")
+	for _, l := range languages {
+		if l.tag == "*" {
+			return locale
+		}
+		tag, err := kal.ParseLocale(l.tag)
+		if err != nil {
+			continue
+		}
+		if c, ok := kal.FindCatalog(tag.Language); ok {
+			if c.Language == lang {
+				return locale
+			}
+			return c.Language + "_" + region
+		}
+	}
+	return locale
+}
+
+// A locale, in the answer from /v1/locales
+type localeInfo struct {
+	Locale      string   `json:"locale"`
+	Language    string   `json:"language"`
+	Region      string   `json:"region"`
+	MondayFirst bool     `json:"monday_first"`
+	Weekend     []string `json:"weekend"`
+}
+
+// Serve the list of locales
+func (h *Handler) locales(w http.ResponseWriter, r *http.Request) {
+	var infos []localeInfo
+	for _, locale := range kal.Locales() {
+		cal, ok := h.calendar(locale)
+		if !ok {
+			continue
+		}
+		language, region, _ := strings.Cut(locale, "_")
+		info := localeInfo{Locale: locale, Language: language, Region: region, MondayFirst: cal.MondayFirst(), Weekend: []string{}}
+		for _, day := range kal.Weekend(cal) {
+			info.Weekend = append(info.Weekend, cal.DayName(day))
+		}
+		infos = append(infos, info)
+	}
+	h.writeJSON(w, r, map[string]interface{}{"locales": infos})
+}
+
+// A red day or a notable day, in the answer from /v1/{locale}/holidays
+type holiday struct {
+	ID      string `json:"id"`
+	Date    string `json:"date"`
+	Weekday string `json:"weekday"`
+	Name    string `json:"name"`
+	Red     bool   `json:"red"`
+	Flag    bool   `json:"flag"`
+	HalfDay string `json:"half_day,omitempty"` // the time of day when the time off starts
+}
+
+// Serve the red days of a year, and the notable days if notable=true
+func (h *Handler) holidays(w http.ResponseWriter, r *http.Request, lr localeRequest) {
+	year := time.Now().Year()
+	if s := r.URL.Query().Get("year"); s != "" {
+		y, err := strconv.Atoi(s)
+		if err != nil || y < 1 || y > 9999 {
+			writeError(w, http.StatusBadRequest, "invalid year: "+s)
+			return
+		}
+		year = y
+	}
+	notable := r.URL.Query().Get("notable") == "true"
+	days := []holiday{}
+	for _, sd := range kal.SpecialDays(lr.cal, time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)) {
+		if !sd.Red && !notable {
+			continue
+		}
+		hd := holiday{ID: sd.ID, Date: sd.Date.Format("2006-01-02"), Weekday: lr.cal.DayName(sd.Date.Weekday()), Name: sd.Name, Red: sd.Red, Flag: sd.Flag}
+		if sd.Start > 0 {
+			hd.HalfDay = sd.Date.Add(sd.Start).Format("15:04")
+		}
+		days = append(days, hd)
+	}
+	h.writeJSON(w, r, map[string]interface{}{"locale": lr.locale, "year": year, "holidays": days})
+}
+
+// The answer from /v1/{locale}/day/{date}
+type dayInfo struct {
+	Date        string `json:"date"`
+	Weekday     string `json:"weekday"`
+	Description string `json:"description"`
+	RedDay      bool   `json:"red_day"`
+	Holiday     bool   `json:"holiday"`
+	Weekend     bool   `json:"weekend"`
+	BusinessDay bool   `json:"business_day"`
+	NotableDay  bool   `json:"notable_day"`
+	FlagDay     bool   `json:"flag_day"`
+	HalfDay     string `json:"half_day,omitempty"`
+	HolidayName string `json:"holiday_name,omitempty"`
+	NotableName string `json:"notable_name,omitempty"`
+}
+
+// Serve the description of a single date
+func (h *Handler) day(w http.ResponseWriter, r *http.Request, lr localeRequest) {
+	date, err := time.Parse("2006-01-02", r.PathValue("date"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid date, use YYYY-MM-DD: "+r.PathValue("date"))
+		return
+	}
+	cal := lr.cal
+	info := dayInfo{
+		Date:        date.Format("2006-01-02"),
+		Weekday:     cal.DayName(date.Weekday()),
+		Description: kal.Describe(cal, date),
+		RedDay:      kal.Holiday(cal, date) || kal.WeekendDay(cal, date),
+		Holiday:     kal.Holiday(cal, date),
+		Weekend:     kal.WeekendDay(cal, date),
+		BusinessDay: kal.BusinessDay(cal, date),
+		FlagDay:     kal.FlagDay(cal, date),
+	}
+	_, info.HolidayName, _ = cal.RedDay(date)
+	info.NotableDay, info.NotableName, _ = cal.NotableDay(date)
+	if half, start := kal.HalfDay(cal, date); half {
+		info.HalfDay = date.Add(start).Format("15:04")
+	}
+	h.writeJSON(w, r, info)
+}
+
+// Serve the business days from and including from, to and including to
+func (h *Handler) businessDays(w http.ResponseWriter, r *http.Request, lr localeRequest) {
+	var dates [2]time.Time
+	for i, name := range []string{"from", "to"} {
+		d, err := time.Parse("2006-01-02", r.URL.Query().Get(name))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid "+name+" date, use YYYY-MM-DD: "+r.URL.Query().Get(name))
+			return
+		}
+		dates[i] = d
+	}
+	from, to := dates[0], dates[1]
+	if to.Before(from) || to.Sub(from) > maxDays*24*time.Hour {
+		writeError(w, http.StatusBadRequest, "the range must be from 0 to "+strconv.Itoa(maxDays)+" days long")
+		return
+	}
+	days := []string{}
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		if kal.BusinessDay(lr.cal, date) {
+			days = append(days, date.Format("2006-01-02"))
+		}
+	}
+	h.writeJSON(w, r, map[string]interface{}{
+		"locale":        lr.locale,
+		"from":          from.Format("2006-01-02"),
+		"to":            to.Format("2006-01-02"),
+		"count":         len(days),
+		"business_days": days,
+	})
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func get(t *testing.T, h http.Handler, path, acceptLanguage string, v interface{}) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", path, nil)
+	if acceptLanguage != "" {
+		req.Header.Set("Accept-Language", acceptLanguage)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if v != nil && rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("%s: %v\n%s", path, err, rec.Body.String())
+		}
+	}
+	return rec
+}
+
+func TestAPI(t *testing.T) {
+	h := NewHandler()
+
+	var locales struct {
+		Locales []localeInfo `json:"locales"`
+	}
+	get(t, h, "/v1/locales", "", &locales)
+	if len(locales.Locales) != 3 || locales.Locales[1].Locale != "nb_NO" {
+		t.Errorf("locales: got %+v", locales)
+	}
+
+	var holidays struct {
+		Year     int       `json:"year"`
+		Holidays []holiday `json:"holidays"`
+	}
+	rec := get(t, h, "/v1/nb_NO/holidays?year=2025", "", &holidays)
+	if holidays.Year != 2025 || len(holidays.Holidays) == 0 || holidays.Holidays[0].Name != "Første nyttårsdag" || rec.Header().Get("Cache-Control") == "" {
+		t.Errorf("holidays: got %+v", holidays)
+	}
+	// The client already has the answer
+	req := httptest.NewRequest("GET", "/v1/nb_NO/holidays?year=2025", nil)
+	req.Header.Set("If-None-Match", rec.Header().Get("ETag"))
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("If-None-Match: got %d", rec.Code)
+	}
+
+	// Norwegian holidays, with the names in English where kal has them
+	rec = get(t, h, "/v1/nb_NO/holidays?year=2025", "en-GB,en;q=0.9", &holidays)
+	if holidays.Holidays[0].Name != "New Year's Day" || holidays.Holidays[0].Weekday != "Wednesday" || rec.Header().Get("Content-Language") != "en" {
+		t.Errorf("holidays in English: got %+v", holidays.Holidays[0])
+	}
+
+	var day dayInfo
+	get(t, h, "/v1/nb_NO/day/2025-05-17", "", &day)
+	if !day.Holiday || !day.FlagDay || !day.Weekend || day.BusinessDay || day.Description != "Grunnlovsdagen" {
+		t.Errorf("day: got %+v", day)
+	}
+	get(t, h, "/v1/en_NO/day/2025-05-17", "", &day)
+	if !day.Holiday || day.Description != "Constitution Day" || day.Weekday != "Saturday" {
+		t.Errorf("day in en_NO: got %+v", day)
+	}
+
+	var businessDays struct {
+		Count int      `json:"count"`
+		Days  []string `json:"business_days"`
+	}
+	get(t, h, "/v1/nb_NO/business-days?from=2025-04-14&to=2025-04-22", "", &businessDays)
+	if businessDays.Count != 4 || businessDays.Days[3] != "2025-04-22" {
+		t.Errorf("business days: got %+v", businessDays)
+	}
+
+	for path, status := range map[string]int{
+		"/v1/xx_XX/holidays":                                    http.StatusNotFound,
+		"/v1/nb_NO/day/17.05.2025":                              http.StatusBadRequest,
+		"/v1/nb_NO/business-days?from=2025-01-02&to=2025-01-01": http.StatusBadRequest,
+	} {
+		if rec := get(t, h, path, "", nil); rec.Code != status {
+			t.Errorf("%s: got %d, want %d", path, rec.Code, status)
+		}
+	}
+}
+
+func TestConcurrentRequests(t *testing.T) {
+	h := NewHandler()
+	// Listing the locales while other requests add calendars, for go test -race
+	var wg sync.WaitGroup
+	for _, path := range []string{"/v1/locales", "/v1/en_NO/day/2025-05-17", "/v1/tr_NO/holidays?year=2030", "/v1/locales", "/v1/nb_US/day/2025-07-04"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest("GET", path, nil)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Errorf("%s: got %d", path, rec.Code)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,202 @@
+package kal
+
+// Calculations with business hours, that take public holidays and half days into account
+
+import (
+	"time"
+)
+
+// The maximum number of days to search forwards or backwards for opening hours
+const maxSearchDays = 3660
+
+// OpeningHours are the opening hours for a day of the week, given as the
+// time of day when opening and closing. If Close is not after Open, it is
+// closed all day.
+type OpeningHours struct {
+	Open  time.Duration
+	Close time.Duration
+}
+
+// BusinessHours are weekly opening hours in a given time zone, that are
+// closed on the public holidays of a Calendar, and that close early on
+// half days.
+type BusinessHours struct {
+	Calendar Calendar
+	Week     [7]OpeningHours // the opening hours, indexed by time.Weekday
+	Location *time.Location
+	Cutoff   time.Duration // the time of day for order cut-off, or 0 for none
+}
+
+// WorkWeek returns opening hours from Monday to Friday, between the given
+// times of day, like 8 * time.Hour and 16 * time.Hour for 08:00-16:00
+func WorkWeek(open, close time.Duration) [7]OpeningHours {
+	var week [7]OpeningHours
+	for weekday := time.Monday; weekday <= time.Friday; weekday++ {
+		week[weekday] = OpeningHours{open, close}
+	}
+	return week
+}
+
+// Create new BusinessHours for the given calendar, opening hours and time zone
+func NewBusinessHours(cal Calendar, week [7]OpeningHours, loc *time.Location) BusinessHours {
+	return BusinessHours{Calendar: cal, Week: week, Location: loc}
+}
+
+// The given time of day at the given date, in the given time zone.
+// time.Date normalizes the nanoseconds, so this works across DST changes.
+func atTimeOfDay(year int, month time.Month, day int, tod time.Duration, loc *time.Location) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, int(tod), loc)
+}
+
+// Find the opening and closing times for the date that is the given number
+// of days after the date of t. Returns false if it is closed all day.
+func (bh BusinessHours) hours(t time.Time, days int) (time.Time, time.Time, bool) {
+	year, month, day := t.Date()
+	date := time.Date(year, month, day+days, 0, 0, 0, 0, bh.Location)
+	year, month, day = date.Date()
+	oh := bh.Week[date.Weekday()]
+	closing := oh.Close
+	if half, start := HalfDay(bh.Calendar, date); half {
+		// Close early on half days
+		if start < closing {
+			closing = start
+		}
+	} else if Holiday(bh.Calendar, date) {
+		return date, date, false
+	}
+	if closing <= oh.Open {
+		return date, date, false
+	}
+	return atTimeOfDay(year, month, day, oh.Open, bh.Location), atTimeOfDay(year, month, day, closing, bh.Location), true
+}
+
+// IsOpen checks if it is open at the given time
+func (bh BusinessHours) IsOpen(t time.Time) bool {
+	t = t.In(bh.Location)
+	open, close, ok := bh.hours(t, 0)
+	return ok && !t.Before(open) && t.Before(close)
+}
+
+// NextOpen returns the given time if it is open at that time, or else the
+// next time it opens. Returns the zero time if it never opens.
+func (bh BusinessHours) NextOpen(t time.Time) time.Time {
+	t = t.In(bh.Location)
+	for i := 0; i < maxSearchDays; i++ {
+		open, close, ok := bh.hours(t, i)
+		if !ok || !t.Before(close) {
+			continue
+		}
+		if t.Before(open) {
+			return open
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// Add adds the given duration of business hours to the given time.
+// A negative duration goes backwards in time. Returns the zero time if
+// there are not enough opening hours within the next ten years.
+func (bh BusinessHours) Add(t time.Time, d time.Duration) time.Time {
+	t = t.In(bh.Location)
+	if d == 0 {
+		return t
+	}
+	if d < 0 {
+		return bh.subtract(t, -d)
+	}
+	for i := 0; i < maxSearchDays; i++ {
+		open, close, ok := bh.hours(t, i)
+		if !ok || !t.Before(close) {
+			continue
+		}
+		start := t
+		if start.Before(open) {
+			start = open
+		}
+		if available := close.Sub(start); d > available {
+			d -= available
+			continue
+		}
+		return start.Add(d)
+	}
+	return time.Time{}
+}
+
+// Go the given duration of business hours back in time from t
+func (bh BusinessHours) subtract(t time.Time, d time.Duration) time.Time {
+	for i := 0; i > -maxSearchDays; i-- {
+		open, close, ok := bh.hours(t, i)
+		if !ok || !open.Before(t) {
+			continue
+		}
+		end := t
+		if end.After(close) {
+			end = close
+		}
+		if available := end.Sub(open); d > available {
+			d -= available
+			continue
+		}
+		return end.Add(-d)
+	}
+	return time.Time{}
+}
+
+// Between returns the business hours between a and b.
+// The duration is negative if b is before a.
+func (bh BusinessHours) Between(a, b time.Time) time.Duration {
+	if b.Before(a) {
+		return -bh.Between(b, a)
+	}
+	a, b = a.In(bh.Location), b.In(bh.Location)
+	var total time.Duration
+	for i := 0; ; i++ {
+		open, close, ok := bh.hours(a, i)
+		if !open.Before(b) {
+			break
+		}
+		if !ok {
+			continue
+		}
+		if open.Before(a) {
+			open = a
+		}
+		if close.After(b) {
+			close = b
+		}
+		if close.After(open) {
+			total += close.Sub(open)
+		}
+	}
+	return total
+}
+
+// CutoffDate returns the date when something that arrives at the given time
+// is handled, for example when an order is shipped. That is the same day,
+// if it is open that day and t is before the cut-off time, or else the
+// next day that is open. Half days that close before the cut-off time use
+// the closing time as the cut-off time instead. If Cutoff is 0, the closing
+// time is used. Returns the zero time if it never opens.
+func (bh BusinessHours) CutoffDate(t time.Time) time.Time {
+	t = t.In(bh.Location)
+	for i := 0; i < maxSearchDays; i++ {
+		open, close, ok := bh.hours(t, i)
+		if !ok {
+			continue
+		}
+		if i > 0 {
+			return time.Date(open.Year(), open.Month(), open.Day(), 0, 0, 0, 0, bh.Location)
+		}
+		cutoff := close
+		if bh.Cutoff > 0 {
+			if c := atTimeOfDay(open.Year(), open.Month(), open.Day(), bh.Cutoff, bh.Location); c.Before(close) {
+				cutoff = c
+			}
+		}
+		if t.Before(cutoff) {
+			return time.Date(open.Year(), open.Month(), open.Day(), 0, 0, 0, 0, bh.Location)
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,46 @@
+package kal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBusinessHours(t *testing.T) {
+	oslo, err := time.LoadLocation("Europe/Oslo")
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+	bh := NewBusinessHours(NewNorwegianCalendar(), WorkWeek(8*time.Hour, 16*time.Hour), oslo)
+	bh.Cutoff = 14 * time.Hour
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2025, month, day, hour, minute, 0, 0, oslo)
+	}
+
+	// Friday 15:00 + 8 business hours is Monday 15:00
+	if got, want := bh.Add(at(time.March, 7, 15, 0), 8*time.Hour), at(time.March, 10, 15, 0); !got.Equal(want) {
+		t.Errorf("Add: got %s, want %s", got, want)
+	}
+	// Julaften closes at 12:00 and the days until 29 December are red or weekend days
+	if got, want := bh.Add(at(time.December, 23, 14, 0), 8*time.Hour), at(time.December, 29, 10, 0); !got.Equal(want) {
+		t.Errorf("Add over Christmas: got %s, want %s", got, want)
+	}
+	if got, want := bh.Add(at(time.December, 29, 10, 0), -8*time.Hour), at(time.December, 23, 14, 0); !got.Equal(want) {
+		t.Errorf("Add backwards over Christmas: got %s, want %s", got, want)
+	}
+	if got, want := bh.Between(at(time.December, 23, 14, 0), at(time.December, 29, 10, 0)), 8*time.Hour; got != want {
+		t.Errorf("Between: got %s, want %s", got, want)
+	}
+	if bh.IsOpen(at(time.December, 24, 12, 30)) || !bh.IsOpen(at(time.December, 24, 11, 30)) {
+		t.Error("IsOpen: expected julaften to be open until 12:00")
+	}
+	// Skjærtorsdag, langfredag, the weekend and andre påskedag are all closed
+	if got, want := bh.NextOpen(at(time.April, 16, 17, 0)), at(time.April, 22, 8, 0); !got.Equal(want) {
+		t.Errorf("NextOpen: got %s, want %s", got, want)
+	}
+	if got, want := bh.CutoffDate(at(time.March, 7, 14, 30)), at(time.March, 10, 0, 0); !got.Equal(want) {
+		t.Errorf("CutoffDate: got %s, want %s", got, want)
+	}
+	if got, want := bh.CutoffDate(at(time.March, 7, 13, 30)), at(time.March, 7, 0, 0); !got.Equal(want) {
+		t.Errorf("CutoffDate: got %s, want %s", got, want)
+	}
+}
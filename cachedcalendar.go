@@ -1,73 +1,194 @@
 package kal
 
 import (
+	"container/list"
+	"sync"
 	"time"
 )
 
-// A CachedCalendar wraps and caches a Calendar
+// A cachedDay is a cached answer from either RedDay or NotableDay.
+// Negative answers are cached too, so known is needed to tell an
+// uncached day apart from a day that is known to be ordinary.
+type cachedDay struct {
+	known bool
+	ok    bool
+	desc  string
+	flag  bool
+}
+
+// A cachedYear holds the cached answers for every day of a single year,
+// indexed by the day of the year, starting at 0
+type cachedYear struct {
+	year    int
+	red     [366]cachedDay
+	notable [366]cachedDay
+}
+
+// CacheStats contains statistics for a CachedCalendar
+type CacheStats struct {
+	Hits   uint64 // lookups that were answered from the cache
+	Misses uint64 // lookups that had to ask the wrapped calendar
+	Years  int    // number of years currently in the cache
+}
+
+// The cached years of a CachedCalendar, shared by the copies of it
+type calendarCache struct {
+	mut      sync.Mutex
+	years    map[int]*list.Element // elements of order, by year
+	order    *list.List            // *cachedYear values, the most recently used year first
+	maxYears int                   // the maximum number of cached years, 0 is unbounded
+	hits     uint64
+	misses   uint64
+}
+
+// A CachedCalendar wraps and caches a Calendar.
+// It is safe for concurrent use by multiple goroutines, and copies of
+// a CachedCalendar share the same cache.
 type CachedCalendar struct {
-	cal          Calendar
-	cacheRed     map[time.Time]string // red day description
-	cacheNotable map[time.Time]string // notable day description
-	cacheFlag    map[time.Time]bool   // flag flying day
+	cal   Calendar
+	cache *calendarCache
 }
 
 // Creates a new CachedCalendar that wraps and caches the given Calendar.
 // A CachedCalendar is also a Calendar itself, since it implements the
-// Calendar interface.
+// Calendar interface. The cache grows with every year that is looked up.
 func NewCachedCalendar(cal Calendar) CachedCalendar {
-	var calca CachedCalendar
-	calca.cal = cal
-	calca.cacheRed = make(map[time.Time]string)
-	calca.cacheNotable = make(map[time.Time]string)
-	calca.cacheFlag = make(map[time.Time]bool)
-	return calca
+	return NewBoundedCachedCalendar(cal, 0)
 }
 
-// Wraps the RedDay function and caches some of the results
-func (calca CachedCalendar) RedDay(date time.Time) (bool, string, bool) {
-	// Return from cache, if it's there
-	desc, ok := calca.cacheRed[date]
-	if ok {
-		return ok, desc, calca.cacheFlag[date]
+// Creates a new CachedCalendar that keeps at most maxYears years in the
+// cache. When a new year is added to a full cache, the least recently used
+// year is evicted. If maxYears is 0 or less, the cache is unbounded.
+func NewBoundedCachedCalendar(cal Calendar, maxYears int) CachedCalendar {
+	if maxYears < 0 {
+		maxYears = 0
+	}
+	return CachedCalendar{
+		cal: cal,
+		cache: &calendarCache{
+			years:    make(map[int]*list.Element),
+			order:    list.New(),
+			maxYears: maxYears,
+		},
 	}
+}
 
-	// Get the information from the calendar
-	red, desc, flag := calca.cal.RedDay(date)
+// Find the cached year for the given year, adding it if needed.
+// The mutex must be held when calling this function.
+func (c *calendarCache) year(year int) *cachedYear {
+	if e, ok := c.years[year]; ok {
+		// Mark the year as the most recently used one
+		c.order.MoveToFront(e)
+		return e.Value.(*cachedYear)
+	}
+	// Make room for the new year, if needed
+	if c.maxYears > 0 && c.order.Len() >= c.maxYears {
+		oldest := c.order.Back()
+		delete(c.years, oldest.Value.(*cachedYear).year)
+		c.order.Remove(oldest)
+	}
+	cy := &cachedYear{year: year}
+	c.years[year] = c.order.PushFront(cy)
+	return cy
+}
 
-	// Add red days to the cache
-	// TODO: Also cache non-red days
-	if red {
-		calca.cacheRed[date] = desc
-		calca.cacheFlag[date] = flag
+// Look up a date in the cache, or ask fn and store the answer.
+// pick selects which of the two tables in a cached year to use.
+func (calca CachedCalendar) lookup(date time.Time, fn func(time.Time) (bool, string, bool), pick func(*cachedYear) *[366]cachedDay) (bool, string, bool) {
+	// Return from cache, if it's there
+	calca.cache.mut.Lock()
+	cd := pick(calca.cache.year(date.Year()))[date.YearDay()-1]
+	if cd.known {
+		calca.cache.hits++
+		calca.cache.mut.Unlock()
+		return cd.ok, cd.desc, cd.flag
 	}
+	calca.cache.misses++
+	calca.cache.mut.Unlock()
+
+	// Get the information from the calendar, without holding the lock
+	ok, desc, flag := fn(date)
+
+	// Add the answer to the cache, also if the day is an ordinary one.
+	// The year may have been evicted in the meantime, which is fine.
+	calca.cache.mut.Lock()
+	pick(calca.cache.year(date.Year()))[date.YearDay()-1] = cachedDay{true, ok, desc, flag}
+	calca.cache.mut.Unlock()
+
+	return ok, desc, flag
+}
+
+func pickRed(cy *cachedYear) *[366]cachedDay {
+	return &cy.red
+}
+
+func pickNotable(cy *cachedYear) *[366]cachedDay {
+	return &cy.notable
+}
 
-	return red, desc, flag
+// Wraps the RedDay function and caches the results
+func (calca CachedCalendar) RedDay(date time.Time) (bool, string, bool) {
+	return calca.lookup(date, calca.cal.RedDay, pickRed)
 }
 
-// Wraps the NotableDay function and caches some of the results
+// Wraps the NotableDay function and caches the results
 func (calca CachedCalendar) NotableDay(date time.Time) (bool, string, bool) {
-	// Return from cache, if it's there
-	desc, ok := calca.cacheNotable[date]
-	if ok {
-		return ok, desc, calca.cacheFlag[date]
-	}
+	return calca.lookup(date, calca.cal.NotableDay, pickNotable)
+}
 
-	// Get the information from the calendar
-	notable, desc, flag := calca.cal.NotableDay(date)
+// Prefill looks up every day of the given year in the wrapped calendar
+// and stores the answers in the cache, in one go
+func (calca CachedCalendar) Prefill(year int) {
+	cy := cachedYear{year: year}
+	current := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for current.Year() == year {
+		i := current.YearDay() - 1
+		red, desc, flag := calca.cal.RedDay(current)
+		cy.red[i] = cachedDay{true, red, desc, flag}
+		notable, desc, flag := calca.cal.NotableDay(current)
+		cy.notable[i] = cachedDay{true, notable, desc, flag}
+		current = current.AddDate(0, 0, 1)
+	}
+	calca.cache.mut.Lock()
+	*calca.cache.year(year) = cy
+	calca.cache.mut.Unlock()
+}
 
-	// Add notable days to cache
-	// TODO: Also cache non-notable days
-	if notable {
-		calca.cacheNotable[date] = desc
-		calca.cacheFlag[date] = flag
+// Evict removes the given year from the cache
+func (calca CachedCalendar) Evict(year int) {
+	calca.cache.mut.Lock()
+	defer calca.cache.mut.Unlock()
+	if e, ok := calca.cache.years[year]; ok {
+		calca.cache.order.Remove(e)
+		delete(calca.cache.years, year)
 	}
+}
+
+// Reset empties the cache and zeroes the statistics
+func (calca CachedCalendar) Reset() {
+	calca.cache.mut.Lock()
+	calca.cache.years = make(map[int]*list.Element)
+	calca.cache.order.Init()
+	calca.cache.hits = 0
+	calca.cache.misses = 0
+	calca.cache.mut.Unlock()
+}
 
-	return notable, desc, flag
+// Stats returns the number of cache hits and misses so far,
+// and the number of years that are currently cached
+func (calca CachedCalendar) Stats() CacheStats {
+	calca.cache.mut.Lock()
+	defer calca.cache.mut.Unlock()
+	return CacheStats{Hits: calca.cache.hits, Misses: calca.cache.misses, Years: len(calca.cache.years)}
 }
 
 // --- These are here just to satisfy the Calendar interface ---
 
+// Find the half days of the wrapped calendar
+func (calca CachedCalendar) HalfDay(date time.Time) (bool, time.Duration) {
+	return HalfDay(calca.cal, date)
+}
+
 // Wraps the NotablePeriod function
 func (calca CachedCalendar) NotablePeriod(date time.Time) (bool, string) {
 	return calca.cal.NotablePeriod(date)
@@ -91,3 +212,36 @@ func (calca CachedCalendar) MonthName(month time.Month) string {
 func (calca CachedCalendar) MondayFirst() bool {
 	return calca.cal.MondayFirst()
 }
+
+// Find the weekend of the wrapped calendar
+func (calca CachedCalendar) Weekend() []time.Weekday {
+	return Weekend(calca.cal)
+}
+
+// Describe what type of day a given date is, using the wrapped calendar
+func (calca CachedCalendar) describe(date time.Time, weekend bool) string {
+	if d, ok := calca.cal.(describer); ok {
+		return d.describe(date, weekend)
+	}
+	return describe(calca.cal, date, weekend)
+}
+
+// Find the first day of the week in the wrapped calendar
+func (calca CachedCalendar) firstWeekday() time.Weekday {
+	return FirstWeekday(calca.cal)
+}
+
+// Find the words for formatting a period with the wrapped calendar
+func (calca CachedCalendar) periodWords() periodWords {
+	return calendarPeriodWords(calca.cal)
+}
+
+// List the special days of the wrapped calendar
+func (calca CachedCalendar) specialDays(year int) []SpecialDay {
+	return calendarSpecialDays(calca.cal, year)
+}
+
+// The catalog with the names of the wrapped calendar
+func (calca CachedCalendar) catalog() *Catalog {
+	return CalendarCatalog(calca.cal)
+}
@@ -0,0 +1,90 @@
+package kal
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// Run with "go test -race" to check that the cache is safe for concurrent use
+func TestCachedCalendarConcurrent(t *testing.T) {
+	uncached := NewNorwegianCalendar()
+	calca := NewBoundedCachedCalendar(uncached, 2)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			year := 2020 + g%3
+			if g == 0 {
+				calca.Prefill(year)
+			}
+			current := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+			for current.Year() == year {
+				for _, pair := range [][2]func(time.Time) (bool, string, bool){
+					{calca.RedDay, uncached.RedDay},
+					{calca.NotableDay, uncached.NotableDay},
+				} {
+					ok1, desc1, flag1 := pair[0](current)
+					ok2, desc2, flag2 := pair[1](current)
+					if ok1 != ok2 || desc1 != desc2 || flag1 != flag2 {
+						t.Errorf("%s: cached (%v, %q, %v) != uncached (%v, %q, %v)", current.Format("2006-01-02"), ok1, desc1, flag1, ok2, desc2, flag2)
+					}
+				}
+				current = current.AddDate(0, 0, 1)
+			}
+			_ = calca.Stats()
+		}(g)
+	}
+	wg.Wait()
+
+	if stats := calca.Stats(); stats.Years > 2 {
+		t.Errorf("expected at most 2 cached years, got %d", stats.Years)
+	}
+}
+
+func TestCachedCalendarNegative(t *testing.T) {
+	calca := NewCachedCalendar(NewNorwegianCalendar())
+	ordinary := time.Date(2021, time.March, 3, 0, 0, 0, 0, time.UTC)
+	calca.RedDay(ordinary)
+	calca.RedDay(ordinary)
+	if stats := calca.Stats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("expected 1 hit and 1 miss for an ordinary day, got %+v", stats)
+	}
+	calca.Prefill(2022)
+	calca.NotableDay(time.Date(2022, time.May, 8, 0, 0, 0, 0, time.UTC))
+	if stats := calca.Stats(); stats.Hits != 2 || stats.Years != 2 {
+		t.Errorf("expected a prefilled year to give cache hits, got %+v", stats)
+	}
+	calca.Evict(2022)
+	if stats := calca.Stats(); stats.Years != 1 {
+		t.Errorf("expected 1 cached year after eviction, got %d", stats.Years)
+	}
+}
+
+func TestCachedCalendarLeastRecentlyUsed(t *testing.T) {
+	calca := NewBoundedCachedCalendar(NewNorwegianCalendar(), 2)
+	for _, year := range []int{2020, 2021, 2020, 2022} {
+		calca.RedDay(time.Date(year, time.May, 17, 0, 0, 0, 0, time.UTC))
+	}
+	// 2021 is the least recently used year, and should have been evicted
+	calca.RedDay(time.Date(2020, time.May, 17, 0, 0, 0, 0, time.UTC))
+	calca.RedDay(time.Date(2021, time.May, 17, 0, 0, 0, 0, time.UTC))
+	if stats := calca.Stats(); stats.Hits != 2 || stats.Misses != 4 || stats.Years != 2 {
+		t.Errorf("expected 2 hits, 4 misses and 2 cached years, got %+v", stats)
+	}
+}
+
+func TestCachedCalendarDescribe(t *testing.T) {
+	uncached := NewOsloBorsCalendar()
+	calca := NewCachedCalendar(uncached)
+	if _, ok := Calendar(calca).(describer); !ok {
+		t.Fatal("expected the cached calendar to forward describe")
+	}
+	for current := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC); current.Year() == 2025; current = current.AddDate(0, 0, 1) {
+		if got, want := Describe(calca, current), Describe(uncached, current); got != want {
+			t.Errorf("%s: got %q, want %q", current.Format("2006-01-02"), got, want)
+		}
+	}
+}
@@ -0,0 +1,554 @@
+// Package caldav serves kal calendars as read-only CalDAV collections (RFC 4791),
+// so that calendar applications can subscribe to the red days and notable days
+package caldav
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xyproto/kal"
+)
+
+// The XML namespaces, and the prefixes they are written with
+const (
+	nsDAV    = "DAV:"
+	nsCalDAV = "urn:ietf:params:xml:ns:caldav"
+	nsCS     = "http://calendarserver.org/ns/"
+)
+
+var prefixes = map[string]string{nsDAV: "D", nsCalDAV: "C", nsCS: "CS"}
+
+// The longest time range that a calendar-query can ask for
+const maxQueryYears = 20
+
+// The largest PROPFIND or REPORT body that is read, in bytes
+const maxBodySize = 1 << 20
+
+// A Handler serves the calendars as CalDAV collections. The collections are
+// found at /{name}/, and the events at /{name}/{uid}.ics. Several calendars
+// can be merged into one collection by joining the names with "+", like
+// /nb_NO+en_US/. The events are generated on the fly, for every request.
+type Handler struct {
+	Calendars map[string]kal.Calendar // the calendars, by name, like "nb_NO"
+	Years     int                     // the number of years before and after the current year that are listed, 1 if 0
+	Now       func() time.Time        // returns the current time, time.Now if nil
+}
+
+// Create a new Handler for the given calendars, by name
+func NewHandler(calendars map[string]kal.Calendar) *Handler {
+	return &Handler{Calendars: calendars}
+}
+
+// A property of a resource, with the XML inside of it
+type property struct {
+	name  xml.Name
+	inner string
+}
+
+// A response in a multistatus reply
+type response struct {
+	href    string
+	found   []property
+	missing []xml.Name
+	status  int // the status of the whole response, instead of the properties, if not 0
+}
+
+// Escape a string for use in XML
+func escape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// Write an XML element for the given name, with a namespace
+// declaration if the namespace has no prefix
+func startElement(sb *strings.Builder, name xml.Name, inner string) {
+	if prefix, ok := prefixes[name.Space]; ok {
+		qualified := prefix + ":" + name.Local
+		if inner == "" {
+			sb.WriteString("<" + qualified + "/>")
+		} else {
+			sb.WriteString("<" + qualified + ">" + inner + "</" + qualified + ">")
+		}
+		return
+	}
+	sb.WriteString(`<X:` + name.Local + ` xmlns:X="` + escape(name.Space) + `"/>`)
+}
+
+// Write a multistatus reply
+func writeMultistatus(w http.ResponseWriter, responses []response) {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	sb.WriteString(`<D:multistatus xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav" xmlns:CS="http://calendarserver.org/ns/">`)
+	for _, r := range responses {
+		sb.WriteString("<D:response><D:href>" + escape(r.href) + "</D:href>")
+		if r.status != 0 {
+			sb.WriteString(fmt.Sprintf("<D:status>HTTP/1.1 %d %s</D:status>", r.status, http.StatusText(r.status)))
+		}
+		if len(r.found) > 0 {
+			sb.WriteString("<D:propstat><D:prop>")
+			for _, p := range r.found {
+				startElement(&sb, p.name, p.inner)
+			}
+			sb.WriteString("</D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat>")
+		}
+		if len(r.missing) > 0 {
+			sb.WriteString("<D:propstat><D:prop>")
+			for _, name := range r.missing {
+				startElement(&sb, name, "")
+			}
+			sb.WriteString("</D:prop><D:status>HTTP/1.1 404 Not Found</D:status></D:propstat>")
+		}
+		sb.WriteString("</D:response>")
+	}
+	sb.WriteString("</D:multistatus>\n")
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	io.WriteString(w, sb.String())
+}
+
+// Select the requested properties from the properties of a resource.
+// If no properties are requested, all of them are returned.
+func selectProperties(href string, props []property, requested []xml.Name) response {
+	r := response{href: href}
+	if requested == nil {
+		r.found = props
+		return r
+	}
+	for _, name := range requested {
+		found := false
+		for _, p := range props {
+			if p.name == name {
+				r.found = append(r.found, p)
+				found = true
+				break
+			}
+		}
+		if !found {
+			r.missing = append(r.missing, name)
+		}
+	}
+	return r
+}
+
+// Create an entity tag from some data
+func etag(data string) string {
+	h := fnv.New64a()
+	io.WriteString(h, data)
+	return fmt.Sprintf(`"%x"`, h.Sum64())
+}
+
+// Find the calendars of a collection, that may be several names joined by "+"
+func (h *Handler) collection(name string) ([]string, bool) {
+	if name == "" {
+		return nil, false
+	}
+	parts := strings.Split(name, "+")
+	for _, part := range parts {
+		if _, ok := h.Calendars[part]; !ok {
+			return nil, false
+		}
+	}
+	return parts, true
+}
+
+// The first and the last date that is listed when no time range is given
+func (h *Handler) window() (time.Time, time.Time) {
+	now := time.Now
+	if h.Now != nil {
+		now = h.Now
+	}
+	years := h.Years
+	if years < 1 {
+		years = 1
+	}
+	year := now().Year()
+	return time.Date(year-years, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(year+years, time.December, 31, 0, 0, 0, 0, time.UTC)
+}
+
+// Find the events of a collection, from and including the date of from, to
+// and including the date of to. The UIDs of merged calendars start with the
+// name of the calendar, to keep them apart.
+func (h *Handler) events(parts []string, from, to time.Time) []kal.ICSEvent {
+	var events []kal.ICSEvent
+	for _, part := range parts {
+		for _, e := range kal.ICSEvents(h.Calendars[part], from, to, kal.ICSOptions{}) {
+			if len(parts) > 1 {
+				e.UID = part + "." + e.UID
+			}
+			events = append(events, e)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Date.Before(events[j].Date) })
+	return events
+}
+
+// Find an event of a collection by the UID
+func (h *Handler) event(parts []string, uid string) (kal.ICSEvent, bool) {
+	// The UID ends with the year, or with the date
+	i := strings.LastIndex(uid, "-")
+	if i < 0 || (len(uid)-i-1 != 4 && len(uid)-i-1 != 8) {
+		return kal.ICSEvent{}, false
+	}
+	var year int
+	if _, err := fmt.Sscanf(uid[i+1:i+5], "%d", &year); err != nil {
+		return kal.ICSEvent{}, false
+	}
+	for _, e := range h.events(parts, time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)) {
+		if e.UID == uid {
+			return e, true
+		}
+	}
+	return kal.ICSEvent{}, false
+}
+
+// The iCalendar data for a single event
+func eventData(e kal.ICSEvent) string {
+	var sb strings.Builder
+	kal.WriteICSEvents(&sb, []kal.ICSEvent{e}, kal.ICSOptions{})
+	return sb.String()
+}
+
+// The href of an event
+func eventHref(name string, e kal.ICSEvent) string {
+	return "/" + name + "/" + url.PathEscape(e.UID) + ".ics"
+}
+
+// Split a path into the name of a collection and the UID of an event.
+// A path like /nb_NO.ics is the collection nb_NO.
+func splitPath(p string) (string, string) {
+	p = strings.Trim(path.Clean("/"+p), "/")
+	name, resource, found := strings.Cut(p, "/")
+	if !found {
+		return strings.TrimSuffix(name, ".ics"), ""
+	}
+	return name, strings.TrimSuffix(resource, ".ics")
+}
+
+// The properties of the root, that is both the principal and the calendar home
+func rootProperties() []property {
+	return []property{
+		{xml.Name{Space: nsDAV, Local: "resourcetype"}, "<D:collection/>"},
+		{xml.Name{Space: nsDAV, Local: "displayname"}, "kal"},
+		{xml.Name{Space: nsDAV, Local: "current-user-principal"}, "<D:href>/</D:href>"},
+		{xml.Name{Space: nsDAV, Local: "principal-URL"}, "<D:href>/</D:href>"},
+		{xml.Name{Space: nsCalDAV, Local: "calendar-home-set"}, "<D:href>/</D:href>"},
+		{xml.Name{Space: nsDAV, Local: "current-user-privilege-set"}, "<D:privilege><D:read/></D:privilege>"},
+	}
+}
+
+// The properties of a collection
+func (h *Handler) collectionProperties(name string, parts []string) []property {
+	from, to := h.window()
+	var tags strings.Builder
+	for _, e := range h.events(parts, from, to) {
+		tags.WriteString(etag(eventData(e)))
+	}
+	tag := etag(tags.String())
+	return []property{
+		{xml.Name{Space: nsDAV, Local: "resourcetype"}, "<D:collection/><C:calendar/>"},
+		{xml.Name{Space: nsDAV, Local: "displayname"}, escape(strings.Join(parts, " + "))},
+		{xml.Name{Space: nsCalDAV, Local: "calendar-description"}, "Red days and notable days"},
+		{xml.Name{Space: nsCalDAV, Local: "supported-calendar-component-set"}, `<C:comp name="VEVENT"/>`},
+		{xml.Name{Space: nsDAV, Local: "supported-report-set"}, "<D:supported-report><D:report><C:calendar-query/></D:report></D:supported-report><D:supported-report><D:report><C:calendar-multiget/></D:report></D:supported-report>"},
+		{xml.Name{Space: nsDAV, Local: "current-user-principal"}, "<D:href>/</D:href>"},
+		{xml.Name{Space: nsDAV, Local: "current-user-privilege-set"}, "<D:privilege><D:read/></D:privilege>"},
+		{xml.Name{Space: nsDAV, Local: "getetag"}, escape(tag)},
+		{xml.Name{Space: nsCS, Local: "getctag"}, escape(tag)},
+	}
+}
+
+// The properties of an event. The calendar data is only included if asked for.
+func eventProperties(data string, withData bool) []property {
+	props := []property{
+		{xml.Name{Space: nsDAV, Local: "resourcetype"}, ""},
+		{xml.Name{Space: nsDAV, Local: "getetag"}, escape(etag(data))},
+		{xml.Name{Space: nsDAV, Local: "getcontenttype"}, "text/calendar; charset=utf-8; component=vevent"},
+		{xml.Name{Space: nsDAV, Local: "getcontentlength"}, fmt.Sprint(len(data))},
+	}
+	if withData {
+		props = append(props, property{xml.Name{Space: nsCalDAV, Local: "calendar-data"}, escape(data)})
+	}
+	return props
+}
+
+// An element with any name, for reading the requested properties
+type anyElement struct {
+	XMLName xml.Name
+}
+
+// The body of a PROPFIND request
+type propfindRequest struct {
+	Prop *struct {
+		Props []anyElement `xml:",any"`
+	} `xml:"DAV: prop"`
+}
+
+// A comp-filter in a calendar-query, with an optional time-range
+type compFilter struct {
+	Name      string `xml:"name,attr"`
+	TimeRange *struct {
+		Start string `xml:"start,attr"`
+		End   string `xml:"end,attr"`
+	} `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+	Filters []compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+// The body of a REPORT request, for a calendar-query or a calendar-multiget
+type reportRequest struct {
+	XMLName xml.Name
+	Prop    struct {
+		Props []anyElement `xml:",any"`
+	} `xml:"DAV: prop"`
+	Hrefs  []string   `xml:"DAV: href"`
+	Filter compFilter `xml:"urn:ietf:params:xml:ns:caldav filter>comp-filter"`
+}
+
+// The names of the requested properties, or nil for all properties
+func propertyNames(elements []anyElement) []xml.Name {
+	var names []xml.Name
+	for _, e := range elements {
+		names = append(names, e.XMLName)
+	}
+	return names
+}
+
+// Checks if the calendar data is among the requested properties
+func wantsData(names []xml.Name) bool {
+	for _, name := range names {
+		if name == (xml.Name{Space: nsCalDAV, Local: "calendar-data"}) {
+			return true
+		}
+	}
+	return false
+}
+
+// Read the body of a request, up to maxBodySize bytes. Returns false if
+// the body could not be read, after replying with an error.
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "the request body is too large", http.StatusRequestEntityTooLarge)
+		} else {
+			http.Error(w, "could not read the request body", http.StatusBadRequest)
+		}
+		return nil, false
+	}
+	return body, true
+}
+
+// Serve a PROPFIND request
+func (h *Handler) propfind(w http.ResponseWriter, r *http.Request) {
+	body, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+	var req propfindRequest
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := xml.Unmarshal(body, &req); err != nil {
+			http.Error(w, "invalid PROPFIND body", http.StatusBadRequest)
+			return
+		}
+	}
+	// An empty body, or allprop, asks for all properties
+	var requested []xml.Name
+	if req.Prop != nil {
+		requested = propertyNames(req.Prop.Props)
+	}
+	depth := r.Header.Get("Depth")
+	name, uid := splitPath(r.URL.Path)
+	var responses []response
+	switch {
+	case name == "":
+		responses = append(responses, selectProperties("/", rootProperties(), requested))
+		if depth != "0" {
+			names := make([]string, 0, len(h.Calendars))
+			for name := range h.Calendars {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				responses = append(responses, selectProperties("/"+name+"/", h.collectionProperties(name, []string{name}), requested))
+			}
+		}
+	case uid == "":
+		parts, ok := h.collection(name)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		responses = append(responses, selectProperties("/"+name+"/", h.collectionProperties(name, parts), requested))
+		if depth != "0" {
+			from, to := h.window()
+			for _, e := range h.events(parts, from, to) {
+				responses = append(responses, selectProperties(eventHref(name, e), eventProperties(eventData(e), wantsData(requested)), requested))
+			}
+		}
+	default:
+		parts, ok := h.collection(name)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		e, ok := h.event(parts, uid)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		responses = append(responses, selectProperties(eventHref(name, e), eventProperties(eventData(e), wantsData(requested)), requested))
+	}
+	writeMultistatus(w, responses)
+}
+
+// Find the time range of a calendar-query, from the comp-filter for VEVENT.
+// Returns false if the query is for other components than events.
+func (h *Handler) timeRange(filter compFilter) (time.Time, time.Time, bool, error) {
+	from, to := h.window()
+	to = to.AddDate(0, 0, 1)
+	if filter.Name != "" && filter.Name != "VCALENDAR" {
+		return from, to, false, nil
+	}
+	for _, f := range filter.Filters {
+		if f.Name != "VEVENT" {
+			return from, to, false, nil
+		}
+		if f.TimeRange == nil {
+			continue
+		}
+		var err error
+		if f.TimeRange.Start != "" {
+			if from, err = time.Parse("20060102T150405Z", f.TimeRange.Start); err != nil {
+				return from, to, false, err
+			}
+		}
+		if f.TimeRange.End != "" {
+			if to, err = time.Parse("20060102T150405Z", f.TimeRange.End); err != nil {
+				return from, to, false, err
+			}
+		}
+		if to.After(from.AddDate(maxQueryYears, 0, 0)) {
+			to = from.AddDate(maxQueryYears, 0, 0)
+		}
+	}
+	return from, to, true, nil
+}
+
+// Serve a REPORT request, for a calendar-query or a calendar-multiget
+func (h *Handler) report(w http.ResponseWriter, r *http.Request) {
+	name, uid := splitPath(r.URL.Path)
+	parts, ok := h.collection(name)
+	if !ok || uid != "" {
+		http.NotFound(w, r)
+		return
+	}
+	body, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+	var req reportRequest
+	if err := xml.Unmarshal(body, &req); err != nil {
+		http.Error(w, "invalid REPORT body", http.StatusBadRequest)
+		return
+	}
+	requested := propertyNames(req.Prop.Props)
+	var responses []response
+	switch req.XMLName {
+	case xml.Name{Space: nsCalDAV, Local: "calendar-query"}:
+		from, to, events, err := h.timeRange(req.Filter)
+		if err != nil {
+			http.Error(w, "invalid time-range", http.StatusBadRequest)
+			return
+		}
+		if events && to.After(from) {
+			// An all-day event overlaps the range if it starts before the
+			// end of the range, and ends after the start of the range
+			for _, e := range h.events(parts, from.AddDate(0, 0, -1), to) {
+				if e.Date.Before(to) && e.Date.AddDate(0, 0, 1).After(from) {
+					responses = append(responses, selectProperties(eventHref(name, e), eventProperties(eventData(e), wantsData(requested)), requested))
+				}
+			}
+		}
+	case xml.Name{Space: nsCalDAV, Local: "calendar-multiget"}:
+		for _, href := range req.Hrefs {
+			u, err := url.Parse(strings.TrimSpace(href))
+			if err != nil {
+				responses = append(responses, response{href: href, status: http.StatusNotFound})
+				continue
+			}
+			hrefName, hrefUID := splitPath(u.Path)
+			e, ok := h.event(parts, hrefUID)
+			if hrefName != name || !ok {
+				responses = append(responses, response{href: href, status: http.StatusNotFound})
+				continue
+			}
+			responses = append(responses, selectProperties(href, eventProperties(eventData(e), wantsData(requested)), requested))
+		}
+	default:
+		http.Error(w, "unsupported report: "+req.XMLName.Local, http.StatusForbidden)
+		return
+	}
+	writeMultistatus(w, responses)
+}
+
+// Serve a GET or HEAD request, for a single event or for a whole collection
+func (h *Handler) get(w http.ResponseWriter, r *http.Request) {
+	name, uid := splitPath(r.URL.Path)
+	parts, ok := h.collection(name)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	var data string
+	if uid != "" {
+		e, ok := h.event(parts, uid)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		data = eventData(e)
+	} else {
+		var sb strings.Builder
+		from, to := h.window()
+		kal.WriteICSEvents(&sb, h.events(parts, from, to), kal.ICSOptions{Name: strings.Join(parts, " + ")})
+		data = sb.String()
+	}
+	tag := etag(data)
+	w.Header().Set("ETag", tag)
+	if match := r.Header.Get("If-None-Match"); match == tag || match == "*" {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	io.WriteString(w, data)
+}
+
+// ServeHTTP serves the read-only CalDAV requests: OPTIONS, GET, HEAD, PROPFIND and REPORT
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const allow = "OPTIONS, GET, HEAD, PROPFIND, REPORT"
+	w.Header().Set("DAV", "1, calendar-access")
+	switch r.Method {
+	case http.MethodOptions:
+		w.Header().Set("Allow", allow)
+	case http.MethodGet, http.MethodHead:
+		h.get(w, r)
+	case "PROPFIND":
+		h.propfind(w, r)
+	case "REPORT":
+		h.report(w, r)
+	default:
+		w.Header().Set("Allow", allow)
+		http.Error(w, "the calendars are read-only", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,279 @@
+package caldav
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	webdav "github.com/emersion/go-webdav"
+	davclient "github.com/emersion/go-webdav/caldav"
+	"github.com/xyproto/kal"
+)
+
+// A multistatus reply, as read by a client
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"DAV: href"`
+		Status   string `xml:"DAV: status"`
+		Propstat []struct {
+			Status string `xml:"DAV: status"`
+			Prop   struct {
+				ETag         string `xml:"DAV: getetag"`
+				CTag         string `xml:"http://calendarserver.org/ns/ getctag"`
+				CalendarData string `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
+				ResourceType struct {
+					Calendar *struct{} `xml:"urn:ietf:params:xml:ns:caldav calendar"`
+				} `xml:"DAV: resourcetype"`
+			} `xml:"DAV: prop"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+func request(t *testing.T, server *httptest.Server, method, path, depth, body string) (*http.Response, string) {
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if depth != "" {
+		req.Header.Set("Depth", depth)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(data)
+}
+
+func TestCalDAV(t *testing.T) {
+	h := NewHandler(map[string]kal.Calendar{"nb_NO": kal.NewNorwegianCalendar(), "en_US": kal.NewUSCalendar()})
+	h.Now = func() time.Time { return time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC) }
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	// Discover the collections
+	resp, body := request(t, server, "PROPFIND", "/", "1", `<D:propfind xmlns:D="DAV:" xmlns:CS="http://calendarserver.org/ns/"><D:prop><D:resourcetype/><CS:getctag/><D:quota-used-bytes/></D:prop></D:propfind>`)
+	var ms multistatus
+	if err := xml.Unmarshal([]byte(body), &ms); err != nil || resp.StatusCode != http.StatusMultiStatus {
+		t.Fatalf("PROPFIND: %d %v\n%s", resp.StatusCode, err, body)
+	}
+	if len(ms.Responses) != 3 || ms.Responses[2].Href != "/nb_NO/" || ms.Responses[2].Propstat[0].Prop.ResourceType.Calendar == nil || ms.Responses[2].Propstat[0].Prop.CTag == "" {
+		t.Fatalf("PROPFIND: got\n%s", body)
+	}
+	if !strings.Contains(ms.Responses[2].Propstat[1].Status, "404") {
+		t.Errorf("expected quota-used-bytes to be not found, got\n%s", body)
+	}
+
+	// Query for the events in May 2025, in Europe/Oslo
+	query := `<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><D:getetag/><C:calendar-data/></D:prop>
+  <C:filter><C:comp-filter name="VCALENDAR"><C:comp-filter name="VEVENT">
+    <C:time-range start="20250430T220000Z" end="20250531T220000Z"/>
+  </C:comp-filter></C:comp-filter></C:filter>
+</C:calendar-query>`
+	_, body = request(t, server, "REPORT", "/nb_NO/", "1", query)
+	ms = multistatus{}
+	if err := xml.Unmarshal([]byte(body), &ms); err != nil {
+		t.Fatal(err)
+	}
+	var hrefs []string
+	for _, r := range ms.Responses {
+		hrefs = append(hrefs, r.Href)
+	}
+	if len(hrefs) < 3 || hrefs[0] != "/nb_NO/labour_day-2025.ics" || hrefs[len(hrefs)-1] != "/nb_NO/ascension_day-2025.ics" {
+		t.Errorf("REPORT: got %v", hrefs)
+	}
+	constitutionDay := ""
+	for _, r := range ms.Responses {
+		if r.Href == "/nb_NO/constitution_day-2025.ics" {
+			constitutionDay = r.Propstat[0].Prop.CalendarData
+			if !strings.Contains(constitutionDay, "SUMMARY:Grunnlovsdagen\r\n") {
+				t.Errorf("got calendar data %q", constitutionDay)
+			}
+			// The ETag of the event is the same for GET
+			resp, data := request(t, server, "GET", r.Href, "", "")
+			if resp.Header.Get("ETag") != r.Propstat[0].Prop.ETag || data != constitutionDay {
+				t.Errorf("GET: got ETag %s, want %s", resp.Header.Get("ETag"), r.Propstat[0].Prop.ETag)
+			}
+			req, _ := http.NewRequest("GET", server.URL+r.Href, nil)
+			req.Header.Set("If-None-Match", r.Propstat[0].Prop.ETag)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusNotModified {
+				t.Errorf("GET with If-None-Match: got %d", resp.StatusCode)
+			}
+		}
+	}
+	if constitutionDay == "" {
+		t.Fatal("constitution day not found")
+	}
+
+	// Merged collections keep the UIDs apart
+	_, body = request(t, server, "REPORT", "/nb_NO+en_US/", "1", strings.NewReplacer("20250430T220000Z", "20251225T000000Z", "20250531T220000Z", "20251226T000000Z").Replace(query))
+	if !strings.Contains(body, "/nb_NO+en_US/nb_NO.christmas_day-2025.ics") || !strings.Contains(body, "/nb_NO+en_US/en_US.christmas_day-2025.ics") {
+		t.Errorf("merged REPORT: got\n%s", body)
+	}
+
+	multiget := `<C:calendar-multiget xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav"><D:prop><D:getetag/></D:prop><D:href>/en_US/independence_day-2026.ics</D:href><D:href>/en_US/nothing-2026.ics</D:href></C:calendar-multiget>`
+	_, body = request(t, server, "REPORT", "/en_US/", "1", multiget)
+	if !strings.Contains(body, "<D:href>/en_US/independence_day-2026.ics</D:href><D:propstat>") || !strings.Contains(body, "404 Not Found") {
+		t.Errorf("multiget: got\n%s", body)
+	}
+
+	if resp, _ := request(t, server, "PUT", "/nb_NO/new.ics", "", "BEGIN:VCALENDAR"); resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("PUT: got %d", resp.StatusCode)
+	}
+}
+
+func TestClientRequests(t *testing.T) {
+	h := NewHandler(map[string]kal.Calendar{"nb_NO": kal.NewNorwegianCalendar(), "en_US": kal.NewUSCalendar()})
+	h.Now = func() time.Time { return time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC) }
+
+	// The requests in testdata are in the form that Thunderbird, Apple
+	// Calendar and DAVx5 send them when subscribing to a calendar
+	for _, tc := range []struct {
+		file   string
+		status int
+		hrefs  []string // hrefs that must be found, with the properties
+		data   bool     // the events include the calendar data
+	}{
+		{"thunderbird-1-propfind.http", http.StatusMultiStatus, []string{"/nb_NO/"}, false},
+		{"thunderbird-2-etags.http", http.StatusMultiStatus, []string{"/nb_NO/", "/nb_NO/constitution_day-2025.ics"}, false},
+		{"thunderbird-3-multiget.http", http.StatusMultiStatus, []string{"/nb_NO/constitution_day-2025.ics", "/nb_NO/christmas_day-2025.ics"}, true},
+		{"apple-1-propfind.http", http.StatusMultiStatus, []string{"/", "/en_US/", "/nb_NO/"}, false},
+		{"apple-2-sync-collection.http", http.StatusForbidden, nil, false},
+		{"apple-3-calendar-query.http", http.StatusMultiStatus, []string{"/nb_NO/labour_day-2025.ics", "/nb_NO/constitution_day-2025.ics", "/nb_NO/ascension_day-2025.ics"}, false},
+		{"apple-4-todo-query.http", http.StatusMultiStatus, nil, false},
+		{"davx5-1-propfind.http", http.StatusMultiStatus, []string{"/nb_NO+en_US/"}, false},
+		{"davx5-2-calendar-query.http", http.StatusMultiStatus, []string{"/nb_NO+en_US/nb_NO.christmas_day-2025.ics", "/nb_NO+en_US/en_US.christmas_day-2025.ics"}, false},
+		{"davx5-3-multiget.http", http.StatusMultiStatus, []string{"/nb_NO+en_US/en_US.christmas_day-2025.ics", "/nb_NO+en_US/nb_NO.boxing_day-2025.ics"}, true},
+	} {
+		f, err := os.Open(filepath.Join("testdata", tc.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, err := http.ReadRequest(bufio.NewReader(f))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.file, err)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		f.Close()
+		if rec.Code != tc.status {
+			t.Errorf("%s: got status %d, want %d\n%s", tc.file, rec.Code, tc.status, rec.Body)
+			continue
+		}
+		if rec.Code != http.StatusMultiStatus {
+			continue
+		}
+		var ms multistatus
+		if err := xml.Unmarshal(rec.Body.Bytes(), &ms); err != nil {
+			t.Fatalf("%s: %v\n%s", tc.file, err, rec.Body)
+		}
+		if tc.hrefs == nil && len(ms.Responses) > 0 {
+			t.Errorf("%s: got %d responses, want none", tc.file, len(ms.Responses))
+		}
+		for _, href := range tc.hrefs {
+			found := false
+			for _, r := range ms.Responses {
+				if r.Href != href {
+					continue
+				}
+				found = true
+				if len(r.Propstat) == 0 || !strings.Contains(r.Propstat[0].Status, "200 OK") {
+					t.Errorf("%s: no properties for %s", tc.file, href)
+				} else if tc.data && !strings.Contains(r.Propstat[0].Prop.CalendarData, "BEGIN:VEVENT") {
+					t.Errorf("%s: no calendar data for %s", tc.file, href)
+				}
+			}
+			if !found {
+				t.Errorf("%s: %s not found in\n%s", tc.file, href, rec.Body)
+			}
+		}
+	}
+
+	// The bodies are limited in size
+	req := httptest.NewRequest("PROPFIND", "/nb_NO/", strings.NewReader("<D:propfind xmlns:D=\"DAV:\">"+strings.Repeat(" ", maxBodySize)+"</D:propfind>"))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("got status %d for a large body, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestClient(t *testing.T) {
+	h := NewHandler(map[string]kal.Calendar{"nb_NO": kal.NewNorwegianCalendar()})
+	server := httptest.NewServer(h)
+	defer server.Close()
+	ctx := context.Background()
+
+	// Discover the collections, the way a client is configured with only the server URL
+	wc, err := webdav.NewClient(http.DefaultClient, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	principal, err := wc.FindCurrentUserPrincipal(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := davclient.NewClient(http.DefaultClient, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	homeSet, err := client.FindCalendarHomeSet(ctx, principal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calendars, err := client.FindCalendars(ctx, homeSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calendars) != 1 || calendars[0].Path != "/nb_NO/" {
+		t.Fatalf("FindCalendars: got %+v", calendars)
+	}
+
+	// Query for the events in May 2025
+	objects, err := client.QueryCalendar(ctx, calendars[0].Path, &davclient.CalendarQuery{
+		CompRequest: davclient.CalendarCompRequest{Name: "VCALENDAR", AllProps: true, AllComps: true},
+		CompFilter: davclient.CompFilter{Name: "VCALENDAR", Comps: []davclient.CompFilter{{
+			Name:  "VEVENT",
+			Start: time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC),
+		}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, o := range objects {
+		if o.Path != "/nb_NO/constitution_day-2025.ics" {
+			continue
+		}
+		found = true
+		events := o.Data.Events()
+		if len(events) != 1 || o.ETag == "" {
+			t.Fatalf("got %d events and ETag %q", len(events), o.ETag)
+		}
+		if summary, err := events[0].Props.Text("SUMMARY"); err != nil || summary != "Grunnlovsdagen" {
+			t.Errorf("got summary %q, %v", summary, err)
+		}
+	}
+	if !found {
+		t.Errorf("constitution day not found in %d objects", len(objects))
+	}
+}
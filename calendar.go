@@ -3,6 +3,7 @@ package kal
 
 import (
 	"errors"
+	"strings"
 	"time"
 )
 
@@ -18,6 +19,73 @@ type Calendar interface {
 	MondayFirst() bool
 }
 
+// The most common weekend, Saturday and Sunday. A new slice is returned
+// every time, so that callers can not change the weekend of other calendars.
+func saturdaySunday() []time.Weekday {
+	return []time.Weekday{time.Saturday, time.Sunday}
+}
+
+// A weekendCalendar is a Calendar with a different weekend definition
+type weekendCalendar struct {
+	Calendar
+	weekend []time.Weekday
+}
+
+// WithWeekend returns a Calendar that is like the given Calendar, but where
+// the given days are the weekend. This can be used for calendars with
+// Friday and Saturday as the weekend, or for six-day work weeks.
+func WithWeekend(cal Calendar, weekend ...time.Weekday) Calendar {
+	return weekendCalendar{cal, append([]time.Weekday{}, weekend...)}
+}
+
+// The days of the weekend
+func (wc weekendCalendar) Weekend() []time.Weekday {
+	return append([]time.Weekday{}, wc.weekend...)
+}
+
+// Checks if a given date is a partial day off in the wrapped calendar
+func (wc weekendCalendar) HalfDay(date time.Time) (bool, time.Duration) {
+	return HalfDay(wc.Calendar, date)
+}
+
+// Describe what type of day a given date is, using the wrapped calendar
+func (wc weekendCalendar) describe(date time.Time, weekend bool) string {
+	if d, ok := wc.Calendar.(describer); ok {
+		return d.describe(date, weekend)
+	}
+	return describe(wc.Calendar, date, weekend)
+}
+
+// Find the words for formatting a period with the wrapped calendar
+func (wc weekendCalendar) periodWords() periodWords {
+	return calendarPeriodWords(wc.Calendar)
+}
+
+// List the special days of the wrapped calendar
+func (wc weekendCalendar) specialDays(year int) []SpecialDay {
+	return calendarSpecialDays(wc.Calendar, year)
+}
+
+// The catalog with the names of the wrapped calendar
+func (wc weekendCalendar) catalog() *Catalog {
+	return CalendarCatalog(wc.Calendar)
+}
+
+// Find the first day of the week in the wrapped calendar
+func (wc weekendCalendar) firstWeekday() time.Weekday {
+	return FirstWeekday(wc.Calendar)
+}
+
+// The locales that have calendars in the language of the region
+var locales = []string{"en_US", "nb_NO", "tr_TR"}
+
+// Locales returns the locales that have calendars in the language of the
+// region, like "nb_NO". NewCalendar also supports the other combinations
+// of these languages and regions, like "en_NO".
+func Locales() []string {
+	return append([]string{}, locales...)
+}
+
 /* Create a new calendar based on a given language string.
  *
  *  Supported strings:
@@ -25,11 +93,21 @@ type Calendar interface {
  *  en_US (US English)
  *  tr_TR (Turkish)
  *
+ *  The languages and the regions can also be combined, like en_NO for the
+ *  Norwegian red days and notable days with English names.
+ *
+ *  Other forms, like "nb-NO", "no_NO.utf8" or "tr_TR@euro", are matched
+ *  with the supported locales by MatchLocale.
+ *
  *  The calendar can be cached for faster lookups
  */
 func NewCalendar(locCode string, cache bool) (cal Calendar, err error) {
+	locale, err := MatchLocale(locCode)
+	if err != nil {
+		return cal, err
+	}
 	// Find the corresponding calendar struct for the given locale
-	switch locCode {
+	switch locale {
 	case "nb_NO":
 		cal = NewNorwegianCalendar()
 	case "en_US":
@@ -37,7 +115,13 @@ func NewCalendar(locCode string, cache bool) (cal Calendar, err error) {
 	case "tr_TR":
 		cal = NewTRCalendar()
 	default:
-		return cal, errors.New("Locale not supported: " + locCode)
+		language, code, _ := strings.Cut(locale, "_")
+		c, ok := FindCatalog(language)
+		r, found := regions[code]
+		if !ok || !found {
+			return cal, errors.New("Locale not supported: " + locCode)
+		}
+		cal = newLocalCalendar(r, c)
 	}
 	if cache {
 		// Return a calendar with cache
@@ -58,9 +142,73 @@ func FlagDay(cal Calendar, date time.Time) bool {
 	return thirdBool(date, cal.RedDay) || thirdBool(date, cal.NotableDay)
 }
 
-// Checks if a given date is a "red" day or not
+// Checks if a given date is a "red" day or not.
+// Weekend days are not red days, unless they are also public holidays,
+// see WeekendDay and BusinessDay.
 func RedDay(cal Calendar, date time.Time) bool {
-	return thirdBool(date, cal.RedDay)
+	red, _, _ := cal.RedDay(date)
+	return red
+}
+
+// Checks if a given date is a public holiday or not.
+// Weekend days are not counted, unless they are also public holidays.
+func Holiday(cal Calendar, date time.Time) bool {
+	red, _, _ := cal.RedDay(date)
+	return red
+}
+
+// halfDayer is implemented by calendars with partial days off
+type halfDayer interface {
+	HalfDay(time.Time) (bool, time.Duration)
+}
+
+// Checks if a given date is a partial day off in the calendar, where only
+// the part of the day after a given time of day is off. Returns true/false
+// and the time of day when the time off starts, like 12 hours for 12:00.
+// Calendars without half days return false.
+func HalfDay(cal Calendar, date time.Time) (bool, time.Duration) {
+	if h, ok := cal.(halfDayer); ok {
+		return h.HalfDay(date)
+	}
+	return false, 0
+}
+
+// weekender is implemented by calendars that define their own weekend
+type weekender interface {
+	Weekend() []time.Weekday
+}
+
+// Weekend returns the days of the weekend in the given calendar. Calendars
+// that do not define a weekend have Saturday and Sunday as the weekend.
+func Weekend(cal Calendar) []time.Weekday {
+	if w, ok := cal.(weekender); ok {
+		return w.Weekend()
+	}
+	return saturdaySunday()
+}
+
+// Checks if a given date is on a weekend, according to the calendar
+func WeekendDay(cal Calendar, date time.Time) bool {
+	weekday := date.Weekday()
+	for _, day := range Weekend(cal) {
+		if day == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks if a given date is a business day, that is, neither a weekend day
+// nor a public holiday. A public holiday that is a half day, like julaften,
+// is a business day until the time off starts, see HalfDay.
+func BusinessDay(cal Calendar, date time.Time) bool {
+	if WeekendDay(cal, date) {
+		return false
+	}
+	if half, _ := HalfDay(cal, date); half {
+		return true
+	}
+	return !Holiday(cal, date)
 }
 
 // Checks if a given date is a notable day or not
@@ -68,11 +216,30 @@ func NotableDay(cal Calendar, date time.Time) bool {
 	return thirdBool(date, cal.NotableDay)
 }
 
-// Describe what type of day a given date is
+// describer is implemented by calendars that can describe a day directly,
+// without combining the results from RedDay and NotableDay
+type describer interface {
+	describe(date time.Time, weekend bool) string
+}
+
+// Describe what type of day a given date is.
+// Weekend days that are not public holidays are described by the name of
+// the day, like "Sunday".
 func Describe(cal Calendar, date time.Time) string {
+	weekend := WeekendDay(cal, date)
+	if d, ok := cal.(describer); ok {
+		return d.describe(date, weekend)
+	}
+	return describe(cal, date, weekend)
+}
+
+// Describe a day by combining the results from RedDay and NotableDay
+func describe(cal Calendar, date time.Time, weekend bool) string {
 	fulldesc := ""
 	if red, desc, _ := cal.RedDay(date); red {
 		fulldesc = desc
+	} else if weekend {
+		fulldesc = capitalize(cal.DayName(date.Weekday()))
 	}
 	if notable, desc, _ := cal.NotableDay(date); notable {
 		if fulldesc == "" {
@@ -87,7 +254,9 @@ func Describe(cal Calendar, date time.Time) string {
 	return cal.NormalDay()
 }
 
-// Return a space separated string of the two first letters of every weekday
+// Return a space separated string of short names for every weekday, of at
+// most two letters. The short names are from the CLDR data for the language
+// of the calendar, if there is any, or else the two first letters of the names.
 func TwoLetterDays(cal Calendar, mondayFirst bool) string {
 	var (
 		i time.Weekday
@@ -98,16 +267,16 @@ func TwoLetterDays(cal Calendar, mondayFirst bool) string {
 			if i != 0 {
 				s += " "
 			}
-			s += string([]rune(cal.DayName(i))[:2])
+			s += shortDayName(cal, i)
 		}
 	} else {
 		for i = 1; i < 7; i++ {
 			if i != 1 {
 				s += " "
 			}
-			s += string([]rune(cal.DayName(i))[:2])
+			s += shortDayName(cal, i)
 		}
-		s += " " + string([]rune(cal.DayName(time.Weekday(0)))[:2])
+		s += " " + shortDayName(cal, time.Sunday)
 	}
 	return s
 }
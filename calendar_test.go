@@ -0,0 +1,225 @@
+package kal
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The compiled calendars give the same answers as the hand-written calendars
+// that came before them, for every weekday from 1990 through 2059.
+// testdata/baseline.txt.gz has the red days and notable days that fell on a
+// weekday, as given by the hand-written calendars. Weekends are left out,
+// since weekend days are no longer red days, and so are the half days,
+// since julaften is no longer a red day, and arife was not included.
+func TestBaseline(t *testing.T) {
+	f, err := os.Open("testdata/baseline.txt.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The expected answers, by locale and date
+	want := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		locale, rest, _ := strings.Cut(scanner.Text(), "\t")
+		date, rest, _ := strings.Cut(rest, "\t")
+		want[locale+" "+date] = rest
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	for _, locale := range []string{"nb_NO", "en_US", "tr_TR"} {
+		cal, err := NewCalendar(locale, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for d := date(1990, time.January, 1); d.Year() < 2060; d = d.AddDate(0, 0, 1) {
+			if half, _ := HalfDay(cal, d); half || d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+				continue
+			}
+			red, redDesc, redFlag := cal.RedDay(d)
+			notable, notableDesc, notableFlag := cal.NotableDay(d)
+			key := locale + " " + d.Format("2006-01-02")
+			expected, ok := want[key]
+			if !ok {
+				if red || notable || Describe(cal, d) != cal.NormalDay() {
+					t.Errorf("%s: expected an ordinary day, got %q, %q", key, redDesc, notableDesc)
+				}
+				continue
+			}
+			got := strings.Join([]string{redDesc, boolString(redFlag), notableDesc, boolString(notableFlag), Describe(cal, d)}, "\t")
+			if got != expected {
+				t.Errorf("%s: got %q, want %q", key, got, expected)
+			}
+		}
+	}
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+func TestWeekend(t *testing.T) {
+	no := NewNorwegianCalendar()
+	saturday, friday := date(2025, time.May, 17), date(2025, time.May, 16)
+	// Grunnlovsdagen on a Saturday is both a public holiday and a weekend day
+	if !RedDay(no, saturday) || !Holiday(no, saturday) || !WeekendDay(no, saturday) || BusinessDay(no, saturday) {
+		t.Error("expected 17 May 2025 to be a red day, a public holiday and a weekend day")
+	}
+	// A Sunday that is not a public holiday is a weekend day, but not a red day
+	if sunday := date(2025, time.March, 9); RedDay(no, sunday) || Holiday(no, sunday) || !WeekendDay(no, sunday) || BusinessDay(no, sunday) {
+		t.Error("expected an ordinary Sunday to be a weekend day, but not a red day")
+	}
+	if RedDay(no, friday) || !BusinessDay(no, friday) {
+		t.Error("expected an ordinary Friday to be a business day")
+	}
+
+	// Friday and Saturday as the weekend
+	cal := WithWeekend(no, time.Friday, time.Saturday)
+	if !WeekendDay(cal, friday) || BusinessDay(cal, friday) || !BusinessDay(cal, date(2025, time.May, 18)) {
+		t.Error("expected Friday to be a weekend day and Sunday to be a business day")
+	}
+	if !Holiday(cal, saturday) || Describe(cal, friday) != "Fredag" {
+		t.Errorf("got %q for Friday, want the wrapped calendar to describe the days", Describe(cal, friday))
+	}
+
+	// Changing the returned weekend does not change the calendars
+	weekend := no.Weekend()
+	weekend[0] = time.Monday
+	if WeekendDay(NewUSCalendar(), date(2025, time.May, 19)) || WeekendDay(no, date(2025, time.May, 19)) {
+		t.Error("expected the weekend of the calendars to be unchanged")
+	}
+	weekend = Weekend(cal)
+	weekend[0] = time.Monday
+	if !WeekendDay(cal, friday) {
+		t.Error("expected the weekend of WithWeekend to be unchanged")
+	}
+
+	// A calendar with only the methods of the Calendar interface has
+	// Saturday and Sunday as the weekend
+	var other Calendar = struct{ Calendar }{plainCalendar{}}
+	if _, ok := other.(weekender); ok || !WeekendDay(other, saturday) || WeekendDay(other, friday) {
+		t.Error("expected Saturday and Sunday to be the weekend of other calendars")
+	}
+}
+
+func TestHalfDay(t *testing.T) {
+	tests := []struct {
+		cal     Calendar
+		date    time.Time
+		name    string
+		holiday bool
+		start   time.Duration
+	}{
+		{NewNorwegianCalendar(), date(2025, time.December, 24), "Julaften", true, 12 * time.Hour},
+		{NewNorwegianCalendar(), date(2025, time.December, 31), "Nyttårsaften", false, 12 * time.Hour},
+		{NewTRCalendar(), date(2026, time.March, 19), "Ramazan Bayramı Arifesi", false, 13 * time.Hour},
+		{NewTRCalendar(), date(2025, time.June, 5), "Kurban Bayramı Arifesi", false, 13 * time.Hour},
+	}
+	for _, test := range tests {
+		half, start := HalfDay(test.cal, test.date)
+		if !half || start != test.start {
+			t.Errorf("%s: got HalfDay %v, %s, want %s", test.name, half, start, test.start)
+		}
+		if desc := Describe(test.cal, test.date); desc != test.name || Holiday(test.cal, test.date) != test.holiday {
+			t.Errorf("%s: got %q, want a holiday: %v", test.name, desc, test.holiday)
+		}
+		// A half day is a business day that closes early, also if it is a red day
+		if !BusinessDay(test.cal, test.date) {
+			t.Errorf("%s: expected a business day", test.name)
+		}
+		bh := NewBusinessHours(test.cal, WorkWeek(8*time.Hour, 16*time.Hour), time.UTC)
+		if !bh.IsOpen(test.date.Add(10*time.Hour)) || bh.IsOpen(test.date.Add(test.start)) {
+			t.Errorf("%s: expected it to be open from 08:00 until %s", test.name, test.start)
+		}
+	}
+	if half, _ := NewTRCalendar().HalfDay(date(2026, time.March, 20)); half {
+		t.Error("the first day of Ramazan Bayramı is not a half day")
+	}
+	// Arife is only known for the years with announced dates
+	if err := NewTRCalendar().CheckYear(2026); err != nil {
+		t.Error(err)
+	}
+	if err := NewTRCalendar().CheckYear(2100); !errors.Is(err, ErrNoData) {
+		t.Errorf("got %v for 2100, want ErrNoData", err)
+	}
+	if half, _ := NewUSCalendar().HalfDay(date(2025, time.December, 24)); half {
+		t.Error("there are no half days in the US calendar")
+	}
+	// A calendar with only the methods of the Calendar interface has no half days
+	if half, _ := HalfDay(struct{ Calendar }{NewNorwegianCalendar()}, date(2025, time.December, 24)); half {
+		t.Error("expected no half days in other calendars")
+	}
+}
+
+// The days of a year, for benchmarking lookups
+func benchmarkDays() []time.Time {
+	var days []time.Time
+	current := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for current.Year() == 2024 {
+		days = append(days, current)
+		current = current.AddDate(0, 0, 1)
+	}
+	return days
+}
+
+func BenchmarkRedDay(b *testing.B) {
+	cal := NewNorwegianCalendar()
+	days := benchmarkDays()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		cal.RedDay(days[i%len(days)])
+	}
+}
+
+func BenchmarkNotableDay(b *testing.B) {
+	cal := NewNorwegianCalendar()
+	days := benchmarkDays()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		cal.NotableDay(days[i%len(days)])
+	}
+}
+
+func BenchmarkDescribe(b *testing.B) {
+	cal := NewNorwegianCalendar()
+	days := benchmarkDays()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Describe(cal, days[i%len(days)])
+	}
+}
+
+// The years share a fixed number of slots, so years that use the same slot
+// must be compiled again, instead of giving the answers for the other year
+func TestTableSlots(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		for _, year := range []int{2025, 2025 + tableSlots, 2025 - tableSlots} {
+			if red, desc, _ := norwegianDays.redDay(date(year, time.May, 17)); !red || desc != "Grunnlovsdagen" {
+				t.Errorf("%d: got %v, %q", year, red, desc)
+			}
+			if yt := norwegianDays.table(year); yt.year != year {
+				t.Errorf("got the table for %d, want %d", yt.year, year)
+			}
+		}
+	}
+}
+
+func BenchmarkCompile(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		norwegianDays.compile(2024)
+	}
+}
@@ -0,0 +1,234 @@
+// Package main is a C API for kal. Build it as a shared library, together
+// with the header libkal.h, with:
+//
+//	go build -buildmode=c-shared -o libkal.so ./capi
+//
+// or as a static library with -buildmode=c-archive and -o libkal.a.
+//
+// Memory ownership:
+//
+//   - A calendar from kal_new_calendar is owned by the caller, and must be
+//     released with kal_free_calendar. Calendars can be used from several
+//     threads at the same time.
+//   - Strings that are returned, directly or through a char** argument, are
+//     allocated with malloc and owned by the caller, who must release them
+//     with kal_free (or free).
+//   - The array from kal_holidays_in_year is owned by the caller, and must be
+//     released with kal_free_holidays, which also releases the strings in it.
+//   - Strings that are given as arguments are only read during the call.
+//
+// Dates are given as year, month (1 to 12) and day (1 to 31).
+package main
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+
+// A red day or a notable day, from kal_holidays_in_year
+typedef struct {
+	int year;
+	int month;
+	int day;
+	int red;    // 1 for a red day (public holiday), 0 for a notable day
+	int flag;   // 1 for a flag flying day
+	char *id;   // identifier for the day, like "easter_sunday"
+	char *name; // description of the day, in the language of the calendar
+} kal_holiday;
+
+// A handle for a calendar, 0 is no calendar
+typedef uintptr_t kal_calendar;
+*/
+import "C"
+
+import (
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/xyproto/kal"
+)
+
+// The calendars that have been handed out, by handle
+var (
+	mut       sync.RWMutex
+	calendars = make(map[C.kal_calendar]kal.Calendar)
+	next      C.kal_calendar
+)
+
+// Find the calendar for a handle
+func calendar(handle C.kal_calendar) (kal.Calendar, bool) {
+	mut.RLock()
+	defer mut.RUnlock()
+	cal, ok := calendars[handle]
+	return cal, ok
+}
+
+// The date for a year, month and day
+func date(year, month, day C.int) time.Time {
+	return time.Date(int(year), time.Month(month), int(day), 0, 0, 0, 0, time.UTC)
+}
+
+// Convert a Go bool to 1 or 0
+func cbool(b bool) C.int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// kal_new_calendar creates a calendar for a locale, like "nb_NO". Returns 0
+// if the locale is not supported. Release the calendar with kal_free_calendar.
+//
+//export kal_new_calendar
+func kal_new_calendar(locale *C.char) C.kal_calendar {
+	cal, err := kal.NewCalendar(C.GoString(locale), true)
+	if err != nil {
+		return 0
+	}
+	mut.Lock()
+	defer mut.Unlock()
+	next++
+	calendars[next] = cal
+	return next
+}
+
+// kal_free_calendar releases a calendar. Releasing 0 does nothing.
+//
+//export kal_free_calendar
+func kal_free_calendar(handle C.kal_calendar) {
+	mut.Lock()
+	defer mut.Unlock()
+	delete(calendars, handle)
+}
+
+// kal_free releases a string that was returned by kal
+//
+//export kal_free
+func kal_free(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
+
+// Answer a question about a day, with the name and the flag day status
+// returned through the pointers, if they are not NULL
+func answer(handle C.kal_calendar, year, month, day C.int, name **C.char, flag *C.int, fn func(kal.Calendar, time.Time) (bool, string, bool)) C.int {
+	cal, ok := calendar(handle)
+	if !ok {
+		return -1
+	}
+	is, desc, isFlag := fn(cal, date(year, month, day))
+	if name != nil {
+		*name = nil
+		if is {
+			*name = C.CString(desc)
+		}
+	}
+	if flag != nil {
+		*flag = cbool(isFlag)
+	}
+	return cbool(is)
+}
+
+// kal_red_day checks if a date is a red day (public holiday), other than a
+// weekend day. Returns 1 or 0, or -1 for an unknown calendar. If name is
+// not NULL, it is set to the description of the red day, that must be
+// released with kal_free, or to NULL. If flag is not NULL, it is set to 1
+// for a flag flying day.
+//
+//export kal_red_day
+func kal_red_day(handle C.kal_calendar, year, month, day C.int, name **C.char, flag *C.int) C.int {
+	return answer(handle, year, month, day, name, flag, func(cal kal.Calendar, date time.Time) (bool, string, bool) {
+		return cal.RedDay(date)
+	})
+}
+
+// kal_notable_day checks if a date is a notable day. Returns 1 or 0, or -1
+// for an unknown calendar. The name and flag are set like for kal_red_day.
+//
+//export kal_notable_day
+func kal_notable_day(handle C.kal_calendar, year, month, day C.int, name **C.char, flag *C.int) C.int {
+	return answer(handle, year, month, day, name, flag, func(cal kal.Calendar, date time.Time) (bool, string, bool) {
+		return cal.NotableDay(date)
+	})
+}
+
+// kal_describe describes a date, like "Grunnlovsdagen" or "Lørdag".
+// The string must be released with kal_free. Returns NULL for an unknown calendar.
+//
+//export kal_describe
+func kal_describe(handle C.kal_calendar, year, month, day C.int) *C.char {
+	cal, ok := calendar(handle)
+	if !ok {
+		return nil
+	}
+	return C.CString(kal.Describe(cal, date(year, month, day)))
+}
+
+// kal_easter finds the date of Easter Sunday for a year, setting the month
+// and the day. Returns 0, or -1 if month or day is NULL.
+//
+//export kal_easter
+func kal_easter(year C.int, month, day *C.int) C.int {
+	if month == nil || day == nil {
+		return -1
+	}
+	easter := kal.EasterDay(int(year))
+	*month = C.int(easter.Month())
+	*day = C.int(easter.Day())
+	return 0
+}
+
+// kal_holidays_in_year finds the red days of a year, and the notable days
+// too if notable is not 0, sorted by date. Sets holidays to an array that
+// must be released with kal_free_holidays, and returns the number of days
+// in it, or -1 for an unknown calendar.
+//
+//export kal_holidays_in_year
+func kal_holidays_in_year(handle C.kal_calendar, year C.int, notable C.int, holidays **C.kal_holiday) C.int {
+	cal, ok := calendar(handle)
+	if !ok || holidays == nil {
+		return -1
+	}
+	var days []kal.SpecialDay
+	for _, sd := range kal.SpecialDays(cal, date(year, 1, 1), date(year, 12, 31)) {
+		if sd.Red || notable != 0 {
+			days = append(days, sd)
+		}
+	}
+	*holidays = nil
+	if len(days) == 0 {
+		return 0
+	}
+	array := (*C.kal_holiday)(C.malloc(C.size_t(len(days)) * C.size_t(unsafe.Sizeof(C.kal_holiday{}))))
+	slice := unsafe.Slice(array, len(days))
+	for i, sd := range days {
+		slice[i] = C.kal_holiday{
+			year:  C.int(sd.Date.Year()),
+			month: C.int(sd.Date.Month()),
+			day:   C.int(sd.Date.Day()),
+			red:   cbool(sd.Red),
+			flag:  cbool(sd.Flag),
+			id:    C.CString(sd.ID),
+			name:  C.CString(sd.Name),
+		}
+	}
+	*holidays = array
+	return C.int(len(days))
+}
+
+// kal_free_holidays releases an array from kal_holidays_in_year, with the
+// given number of days in it
+//
+//export kal_free_holidays
+func kal_free_holidays(holidays *C.kal_holiday, count C.int) {
+	if holidays == nil {
+		return
+	}
+	for _, h := range unsafe.Slice(holidays, int(count)) {
+		C.free(unsafe.Pointer(h.id))
+		C.free(unsafe.Pointer(h.name))
+	}
+	C.free(unsafe.Pointer(holidays))
+}
+
+// A main function is needed for building a C library
+func main() {}
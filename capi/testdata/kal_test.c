@@ -0,0 +1,70 @@
+// Test program for the C API of kal, built and run by capi_test.go
+#include <stdio.h>
+#include <string.h>
+
+#include "libkal.h"
+
+static int failed = 0;
+
+#define check(cond)                                                  \
+    do {                                                             \
+        if (!(cond)) {                                               \
+            fprintf(stderr, "%s:%d: failed: %s\n", __FILE__, __LINE__, #cond); \
+            failed = 1;                                              \
+        }                                                            \
+    } while (0)
+
+int main(void)
+{
+    kal_calendar cal = kal_new_calendar("nb_NO");
+    check(cal != 0);
+    check(kal_new_calendar("xx_XX") == 0);
+
+    // Constitution Day is a red day and a flag flying day
+    char* name = NULL;
+    int flag = 0;
+    check(kal_red_day(cal, 2025, 5, 17, &name, &flag) == 1);
+    check(name != NULL && strcmp(name, "Grunnlovsdagen") == 0);
+    check(flag == 1);
+    kal_free(name);
+
+    // An ordinary Tuesday, where the name is set to NULL
+    name = "not changed";
+    check(kal_red_day(cal, 2025, 5, 20, &name, NULL) == 0);
+    check(name == NULL);
+
+    // Mother's Day is a notable day, and the arguments may be NULL
+    check(kal_notable_day(cal, 2025, 2, 9, NULL, NULL) == 1);
+
+    char* desc = kal_describe(cal, 2025, 12, 25);
+    check(desc != NULL && strcmp(desc, "Første juledag") == 0);
+    kal_free(desc);
+
+    int month = 0, day = 0;
+    check(kal_easter(2025, &month, &day) == 0);
+    check(month == 4 && day == 20);
+
+    kal_holiday* holidays = NULL;
+    int count = kal_holidays_in_year(cal, 2025, 0, &holidays);
+    check(count == 14);
+    if (count > 0) {
+        check(holidays[0].month == 1 && holidays[0].day == 1 && holidays[0].red == 1);
+        check(strcmp(holidays[count - 1].name, "Andre juledag") == 0);
+    }
+    kal_free_holidays(holidays, count);
+
+    count = kal_holidays_in_year(cal, 2025, 1, &holidays);
+    check(count > 14);
+    kal_free_holidays(holidays, count);
+
+    // A released calendar can not be used
+    kal_free_calendar(cal);
+    check(kal_red_day(cal, 2025, 5, 17, NULL, NULL) == -1);
+    check(kal_describe(cal, 2025, 5, 17) == NULL);
+
+    if (failed) {
+        return 1;
+    }
+    printf("ok\n");
+    return 0;
+}
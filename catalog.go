@@ -0,0 +1,420 @@
+package kal
+
+// Translation catalogs, and calendars that combine the rules of a region
+// with the names of another language, like English names for the Norwegian
+// red days in the en_NO locale
+
+import (
+	"sync"
+	"time"
+)
+
+// A Catalog has the names of the days, months and special days in a language
+type Catalog struct {
+	Language  string            // ISO 639 code, like "nb"
+	Days      [7]string         // the days of the week, starting with Sunday
+	Months    [12]string        // the months, starting with January
+	NormalDay string            // an ordinary day, like "Hverdag"
+	FlagDay   string            // a flag flying day, like "flaggdag"
+	Names     map[string]string // the names of the special days, by identifier, like "easter_sunday"
+	period    periodWords
+}
+
+// Finds the name for a day of the week
+func (c *Catalog) DayName(day time.Weekday) string {
+	return c.Days[int(day)]
+}
+
+// Finds the name for a given month
+func (c *Catalog) MonthName(month time.Month) string {
+	return c.Months[int(month)-1]
+}
+
+// Finds the name of a special day, by its identifier. Returns the given
+// name if the catalog has no name for the day.
+func (c *Catalog) Name(id, name string) string {
+	if translated, ok := c.Names[id]; ok {
+		return translated
+	}
+	return name
+}
+
+// The words for formatting a period, English if the catalog has none
+func (c *Catalog) periodWords() periodWords {
+	if c.period == (periodWords{}) {
+		return englishPeriodWords
+	}
+	return c.period
+}
+
+// The Norwegian (Bokmål) names
+var norwegianCatalog = &Catalog{
+	Language:  "nb",
+	Days:      [7]string{"søndag", "mandag", "tirsdag", "onsdag", "torsdag", "fredag", "lørdag"},
+	Months:    [12]string{"januar", "februar", "mars", "april", "mai", "juni", "juli", "august", "september", "oktober", "november", "desember"},
+	NormalDay: "Hverdag",
+	FlagDay:   "flaggdag",
+	Names: map[string]string{
+		// Norway
+		"new_years_day":              "Første nyttårsdag",
+		"palm_sunday":                "Palmesøndag",
+		"maundy_thursday":            "Skjærtorsdag",
+		"good_friday":                "Langfredag",
+		"easter_sunday":              "Første påskedag",
+		"easter_monday":              "Andre påskedag",
+		"labour_day":                 "Arbeidernes internasjonale kampdag",
+		"constitution_day":           "Grunnlovsdagen",
+		"ascension_day":              "Kristi himmelfartsdag",
+		"whit_sunday":                "Første pinsedag",
+		"whit_monday":                "Andre pinsedag",
+		"christmas_day":              "Første juledag",
+		"boxing_day":                 "Andre juledag",
+		"liberation_day":             "Frigjøringsdagen",
+		"sami_national_day":          "Samefolkets dag",
+		"princess_ingrid_alexandra":  "H.K.H. Prinsesse Ingrid Alexandras fødselsdag",
+		"king_harald":                "H.M. Kong Harald Vs fødselsdag",
+		"union_dissolution":          "Unionsoppløsningen med Sverige i 1905",
+		"queen_sonja":                "H.M. Dronning Sonjas fødselsdag",
+		"crown_prince_haakon":        "H.K.H. Kronprins Haakon Magnus' fødselsdag",
+		"olsok":                      "Olsokdagen",
+		"crown_princess_mette_marit": "H.K.H. Kronprinsesse Mette Marits fødselsdag",
+		"parliamentary_election":     "Stortingsvalg-dagen",
+		"ash_wednesday":              "Askeonsdag",
+		"christmas_eve":              "Julaften",
+		"easter_eve":                 "Påskeaften",
+		"shrove_sunday":              "Fastelavnsøndag",
+		"shrove_monday":              "Blåmandag",
+		"shrove_tuesday":             "Feitetirsdag (Mardi Gras)",
+		"midsummer_eve":              "Sankthansaften",
+		"new_years_eve":              "Nyttårsaften",
+		"mothers_day":                "Morsdag",
+		"fathers_day":                "Farsdag",
+		"valentines_day":             "Valentinsdagen",
+		"halloween":                  "Allehelgensaften (Halloween)",
+		"all_saints_day":             "Allehelgensdag",
+		"march_equinox":              "Vårjevndøgn",
+		"june_solstice":              "Sommersolverv",
+		"september_equinox":          "Høstjevndøgn",
+		"december_solstice":          "Vintersolverv",
+		"summer_time":                "Sommertid (+1t)",
+		"winter_time":                "Vintertid (-1t)",
+
+		// US
+		"election_day":           "Valgdagen",
+		"martin_luther_king_day": "Martin Luther King-dagen",
+		"inauguration_day":       "Innsettelsesdagen",
+		"lincolns_birthday":      "Lincolns fødselsdag",
+		"presidents_day":         "Presidentenes dag",
+		"armed_forces_day":       "De væpnede styrkers dag",
+		"memorial_day":           "Minnedagen",
+		"independence_day":       "Uavhengighetsdagen",
+		"labor_day":              "Arbeidernes dag",
+		"columbus_day":           "Columbusdagen",
+		"veterans_day":           "Veterandagen",
+		"thanksgiving_day":       "Takkefesten",
+
+		// Turkey
+		"national_sovereignty_day": "Nasjonal suverenitets- og barnedag",
+		"ataturk_commemoration":    "Minnedag for Atatürk, ungdoms- og idrettsdagen",
+		"democracy_day":            "Demokratiets og den nasjonale enhets dag",
+		"victory_day":              "Seiersdagen",
+		"republic_day":             "Republikkdagen",
+		"ramadan_feast_eve":        "Aften før id al-fitr",
+		"sacrifice_feast_eve":      "Aften før id al-adha",
+	},
+	period: periodWords{"år", "år", "måned", "måneder", "dag", "dager", "og"},
+}
+
+// The English names
+var englishCatalog = &Catalog{
+	Language:  "en",
+	Days:      [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	Months:    [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+	NormalDay: "Ordinary",
+	FlagDay:   "flag day",
+	Names: map[string]string{
+		// US
+		"election_day":           "Election Day",
+		"new_years_day":          "New Year's Day",
+		"martin_luther_king_day": "Martin Luther King Day",
+		"inauguration_day":       "Inauguration Day",
+		"lincolns_birthday":      "Lincoln's birthday",
+		"presidents_day":         "Presidents' Day",
+		"armed_forces_day":       "Armed Forces Day",
+		"memorial_day":           "Memorial Day",
+		"independence_day":       "Independence Day",
+		"labor_day":              "Labor Day",
+		"columbus_day":           "Columbus Day",
+		"veterans_day":           "Veterans Day",
+		"thanksgiving_day":       "Thanksgiving Day",
+		"christmas_day":          "Christmas Day",
+
+		// Norway
+		"palm_sunday":                "Palm Sunday",
+		"maundy_thursday":            "Maundy Thursday",
+		"good_friday":                "Good Friday",
+		"easter_sunday":              "Easter Sunday",
+		"easter_monday":              "Easter Monday",
+		"labour_day":                 "Labour Day",
+		"constitution_day":           "Constitution Day",
+		"ascension_day":              "Ascension Day",
+		"whit_sunday":                "Whit Sunday",
+		"whit_monday":                "Whit Monday",
+		"christmas_eve":              "Christmas Eve",
+		"boxing_day":                 "Boxing Day",
+		"liberation_day":             "Liberation Day",
+		"sami_national_day":          "Sami National Day",
+		"princess_ingrid_alexandra":  "Princess Ingrid Alexandra's birthday",
+		"king_harald":                "King Harald V's birthday",
+		"union_dissolution":          "Dissolution of the union with Sweden in 1905",
+		"queen_sonja":                "Queen Sonja's birthday",
+		"crown_prince_haakon":        "Crown Prince Haakon Magnus' birthday",
+		"olsok":                      "St. Olaf's Day",
+		"crown_princess_mette_marit": "Crown Princess Mette-Marit's birthday",
+		"parliamentary_election":     "Parliamentary election day",
+		"ash_wednesday":              "Ash Wednesday",
+		"easter_eve":                 "Easter Eve",
+		"shrove_sunday":              "Shrove Sunday",
+		"shrove_monday":              "Shrove Monday",
+		"shrove_tuesday":             "Shrove Tuesday (Mardi Gras)",
+		"midsummer_eve":              "Midsummer Eve",
+		"new_years_eve":              "New Year's Eve",
+		"mothers_day":                "Mother's Day",
+		"fathers_day":                "Father's Day",
+		"valentines_day":             "Valentine's Day",
+		"halloween":                  "Halloween",
+		"all_saints_day":             "All Saints' Day",
+		"march_equinox":              "March equinox",
+		"june_solstice":              "June solstice",
+		"september_equinox":          "September equinox",
+		"december_solstice":          "December solstice",
+		"summer_time":                "Summer time (+1h)",
+		"winter_time":                "Winter time (-1h)",
+
+		// Turkey
+		"national_sovereignty_day": "National Sovereignty and Children's Day",
+		"ataturk_commemoration":    "Commemoration of Atatürk, Youth and Sports Day",
+		"democracy_day":            "Democracy and National Unity Day",
+		"victory_day":              "Victory Day",
+		"republic_day":             "Republic Day",
+		"ramadan_feast_eve":        "Ramadan Feast Eve",
+		"sacrifice_feast_eve":      "Sacrifice Feast Eve",
+	},
+}
+
+// The Turkish names
+var turkishCatalog = &Catalog{
+	Language:  "tr",
+	Days:      [7]string{"pazar", "pazartesi", "salı", "çarşamba", "perşembe", "cuma", "cumartesi"},
+	Months:    [12]string{"ocak", "şubat", "mart", "nisan", "mayıs", "haziran", "temmuz", "ağustos", "eylül", "ekim", "kasım", "aralık"},
+	NormalDay: "Sıradan",
+	FlagDay:   "bayrak günü",
+	Names: map[string]string{
+		// Turkey
+		"new_years_day":            "Yılbaşı",
+		"national_sovereignty_day": "Ulusal Egemenlik ve Çocuk Bayramı",
+		"labour_day":               "İşçi Bayramı",
+		"ataturk_commemoration":    "Atatürk'ü Anma, Gençlik ve Spor Bayramı",
+		"democracy_day":            "Demokrasi ve Milli Birlik Günü",
+		"victory_day":              "Zafer Bayramı",
+		"republic_day":             "Cumhuriyet Bayramı",
+		"ramadan_feast_eve":        "Ramazan Bayramı Arifesi",
+		"sacrifice_feast_eve":      "Kurban Bayramı Arifesi",
+
+		// Norway
+		"palm_sunday":      "Palmiye Pazarı",
+		"maundy_thursday":  "Kutsal Perşembe",
+		"good_friday":      "Kutsal Cuma",
+		"easter_sunday":    "Paskalya",
+		"easter_monday":    "Paskalya Pazartesisi",
+		"constitution_day": "Anayasa Günü",
+		"ascension_day":    "Göğe Yükseliş Günü",
+		"whit_sunday":      "Pentekost",
+		"whit_monday":      "Pentekost Pazartesisi",
+		"christmas_eve":    "Noel Arifesi",
+		"christmas_day":    "Noel",
+		"boxing_day":       "Noel'in İkinci Günü",
+		"new_years_eve":    "Yılbaşı Gecesi",
+		"mothers_day":      "Anneler Günü",
+		"fathers_day":      "Babalar Günü",
+		"valentines_day":   "Sevgililer Günü",
+		"halloween":        "Cadılar Bayramı",
+
+		// US
+		"election_day":           "Seçim Günü",
+		"martin_luther_king_day": "Martin Luther King Günü",
+		"inauguration_day":       "Yemin Töreni Günü",
+		"lincolns_birthday":      "Lincoln'ün Doğum Günü",
+		"presidents_day":         "Başkanlar Günü",
+		"armed_forces_day":       "Silahlı Kuvvetler Günü",
+		"memorial_day":           "Anma Günü",
+		"independence_day":       "Bağımsızlık Günü",
+		"labor_day":              "Emek Günü",
+		"columbus_day":           "Kolomb Günü",
+		"veterans_day":           "Gaziler Günü",
+		"thanksgiving_day":       "Şükran Günü",
+	},
+	period: periodWords{"yıl", "yıl", "ay", "ay", "gün", "gün", "ve"},
+}
+
+// The catalogs, by language
+var catalogs = map[string]*Catalog{
+	"nb": norwegianCatalog,
+	"en": englishCatalog,
+	"tr": turkishCatalog,
+}
+
+// FindCatalog finds the catalog for a language, like "nb" or "no". For
+// languages without a catalog, but with CLDR data, a catalog is made with
+// the names of the days and the months from the CLDR data.
+func FindCatalog(language string) (*Catalog, bool) {
+	if alias, ok := languageAliases[language]; ok {
+		language = alias
+	}
+	if c, ok := catalogs[language]; ok {
+		return c, true
+	}
+	return cldrCatalog(language)
+}
+
+// cataloger is implemented by calendars that take their names from a catalog
+type cataloger interface {
+	catalog() *Catalog
+}
+
+// CalendarCatalog returns the catalog with the names of the given calendar.
+// For calendars that are not made from a catalog, a catalog is made from
+// the names that the calendar gives, with English for the rest.
+func CalendarCatalog(cal Calendar) *Catalog {
+	if c, ok := cal.(cataloger); ok {
+		return c.catalog()
+	}
+	c := &Catalog{NormalDay: cal.NormalDay(), FlagDay: englishCatalog.FlagDay, Names: map[string]string{}, period: calendarPeriodWords(cal)}
+	for i := range c.Days {
+		c.Days[i] = cal.DayName(time.Weekday(i))
+	}
+	for i := range c.Months {
+		c.Months[i] = cal.MonthName(time.Month(i + 1))
+	}
+	return c
+}
+
+// A region has the rules for which days are red days and notable days in a country
+type region struct {
+	code     string // ISO 3166 code, like "NO"
+	rules    []rule
+	halfDays map[string]time.Duration // rule ids for partial days off, and when they start
+}
+
+// The regions, by ISO 3166 code
+var regions = map[string]region{
+	"NO": {"NO", norwegianRules, norwegianHalfDays},
+	"US": {"US", usRules, nil},
+	"TR": {"TR", trRules, trHalfDays},
+}
+
+// The compiled rules of the regions, by region and language, like "NO_en"
+var localRuleSets sync.Map
+
+// Find the rules of a region, with the names from the given catalog. Names
+// that are missing from the catalog are taken from the English catalog, or
+// else the identifier is used. The ruleSet, with the compiled years, is
+// shared by all the calendars with the same region and language.
+func localRules(r region, c *Catalog) *ruleSet {
+	key := r.code + "_" + c.Language
+	if rs, ok := localRuleSets.Load(key); ok {
+		return rs.(*ruleSet)
+	}
+	rules := make([]rule, len(r.rules))
+	for i, ru := range r.rules {
+		ru.name = c.Name(ru.id, englishCatalog.Name(ru.id, ru.id))
+		rules[i] = ru
+	}
+	rs, _ := localRuleSets.LoadOrStore(key, newRuleSet(c.DayName, rules).withHalfDays(r.halfDays))
+	return rs.(*ruleSet)
+}
+
+// A localCalendar is a calendar for a region, with the names from a catalog
+type localCalendar struct {
+	region region
+	days   *ruleSet // the rules of the region, with the names from the catalog
+	names  *Catalog
+}
+
+// Create a new calendar for the given region, with the names from the given catalog
+func newLocalCalendar(r region, c *Catalog) localCalendar {
+	return localCalendar{r, localRules(r, c), c}
+}
+
+// The name of a day of the week, from the catalog
+func (lc localCalendar) DayName(day time.Weekday) string {
+	return lc.names.DayName(day)
+}
+
+// The name of a month, from the catalog
+func (lc localCalendar) MonthName(month time.Month) string {
+	return lc.names.MonthName(month)
+}
+
+// Checks if a given date is a public holiday in the region
+func (lc localCalendar) RedDay(date time.Time) (bool, string, bool) {
+	return lc.days.redDay(date)
+}
+
+// Checks if a given date is a notable day in the region
+func (lc localCalendar) NotableDay(date time.Time) (bool, string, bool) {
+	return lc.days.notableDay(date)
+}
+
+// Checks if a given date is a partial day off in the region
+func (lc localCalendar) HalfDay(date time.Time) (bool, time.Duration) {
+	return lc.days.halfDay(date)
+}
+
+// There are no notable periods for the regions
+func (lc localCalendar) NotablePeriod(date time.Time) (bool, string) {
+	return false, ""
+}
+
+// Checks if the week starts on Monday, from the week data of the region
+func (lc localCalendar) MondayFirst() bool {
+	return regionWeek(lc.region.code).firstDay == time.Monday
+}
+
+// The days of the weekend, from the week data of the region
+func (lc localCalendar) Weekend() []time.Weekday {
+	return regionWeek(lc.region.code).weekend()
+}
+
+// The first day of the week, from the week data of the region
+func (lc localCalendar) firstWeekday() time.Weekday {
+	return regionWeek(lc.region.code).firstDay
+}
+
+// An ordinary day, from the catalog
+func (lc localCalendar) NormalDay() string {
+	return lc.names.NormalDay
+}
+
+// Describe what type of day a given date is
+func (lc localCalendar) describe(date time.Time, weekend bool) string {
+	if desc, ok := lc.days.describe(date, weekend); ok {
+		return desc
+	}
+	return lc.NormalDay()
+}
+
+// List the red days and notable days of the given year
+func (lc localCalendar) specialDays(year int) []SpecialDay {
+	return lc.days.specialDays(year, true, true)
+}
+
+// The words for the parts of a period, from the catalog
+func (lc localCalendar) periodWords() periodWords {
+	return lc.names.periodWords()
+}
+
+// The catalog with the names of the calendar
+func (lc localCalendar) catalog() *Catalog {
+	return lc.names
+}
@@ -0,0 +1,63 @@
+package kal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLocalCalendar(t *testing.T) {
+	cal, err := NewCalendar("en_NO", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	constitutionDay := time.Date(2025, time.May, 17, 0, 0, 0, 0, time.UTC)
+	if red, desc, flag := cal.RedDay(constitutionDay); !red || desc != "Constitution Day" || !flag {
+		t.Errorf("en_NO: got %v, %q, %v", red, desc, flag)
+	}
+	if got := Describe(cal, time.Date(2025, time.May, 20, 0, 0, 0, 0, time.UTC)); got != "Ordinary" {
+		t.Errorf("en_NO: got %q for an ordinary day", got)
+	}
+	if !cal.MondayFirst() || cal.MonthName(time.May) != "May" {
+		t.Errorf("en_NO: got the wrong region or language")
+	}
+	if half, start := HalfDay(cal, time.Date(2025, time.December, 24, 0, 0, 0, 0, time.UTC)); !half || start != 12*time.Hour {
+		t.Errorf("en_NO: got %v, %v for Christmas Eve", half, start)
+	}
+
+	// US red days with Norwegian names, and with US red days that have no Norwegian name
+	cal, err = NewCalendar("nb_US", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Describe(cal, time.Date(2025, time.July, 4, 0, 0, 0, 0, time.UTC)); got != "Uavhengighetsdagen" {
+		t.Errorf("nb_US: got %q", got)
+	}
+	if got := CalendarCatalog(cal).FlagDay; got != "flaggdag" {
+		t.Errorf("nb_US: got %q as the word for flag days", got)
+	}
+	if got := CalendarCatalog(NewNYSECalendar()).FlagDay; got != "flag day" {
+		t.Errorf("NYSE: got %q as the word for flag days", got)
+	}
+
+	// Names that are missing from the catalog are in English, not in the language of the region
+	cal, err = NewCalendar("tr_NO", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, desc, _ := cal.NotableDay(time.Date(2025, time.May, 8, 0, 0, 0, 0, time.UTC)); desc != "Liberation Day" {
+		t.Errorf("tr_NO: got %q", desc)
+	}
+	if _, desc, _ := cal.RedDay(constitutionDay); desc != "Anayasa Günü" {
+		t.Errorf("tr_NO: got %q", desc)
+	}
+
+	// The compiled rules are shared by the calendars with the same region and language
+	if newLocalCalendar(regions["NO"], norwegianCatalog).days != norwegianDays {
+		t.Error("nb_NO: the rules are not shared with the Norwegian calendar")
+	}
+	a, _ := NewCalendar("en_NO", false)
+	b, _ := NewCalendar("en-NO", false)
+	if a.(localCalendar).days != b.(localCalendar).days {
+		t.Error("en_NO: the rules are compiled more than once")
+	}
+}
@@ -0,0 +1,159 @@
+package kal
+
+// Names of days and months, and week conventions, from the Unicode CLDR data
+
+//go:generate go run cldr_gen.go
+
+import (
+	"strings"
+	"time"
+)
+
+// NameWidth is the width of the name of a day or a month
+type NameWidth int
+
+const (
+	Wide        NameWidth = iota // the full name, like "Monday"
+	Abbreviated                  // like "Mon"
+	Short                        // like "Mo", for days. Months use the abbreviated names.
+	Narrow                       // like "M"
+)
+
+// cldrNames are the names of the days and the months in a language
+type cldrNames struct {
+	days   [4][7]string  // wide, abbreviated, short and narrow, starting with Sunday
+	months [3][12]string // wide, abbreviated and narrow, starting with January
+}
+
+// cldrWeek is the week data for a region
+type cldrWeek struct {
+	firstDay     time.Weekday
+	minDays      int
+	weekendStart time.Weekday
+	weekendEnd   time.Weekday
+}
+
+// WeekData is how the weeks are counted in a region
+type WeekData struct {
+	FirstDay time.Weekday   // the first day of the week
+	MinDays  int            // the minimal number of days in the first week of a year
+	Weekend  []time.Weekday // the days of the weekend
+}
+
+// Find the CLDR names for the language of a locale, like "nb_NO" or "nb"
+func localeNames(locale string) (*cldrNames, bool) {
+	l, err := ParseLocale(locale)
+	if err != nil {
+		return nil, false
+	}
+	if alias, ok := languageAliases[l.Language]; ok {
+		l.Language = alias
+	}
+	names, ok := cldrLanguages[l.Language]
+	return names, ok
+}
+
+// LocaleDayName finds the name of a day of the week in the language of a
+// locale, like "nb_NO" or "ar". Returns false if there is no CLDR data for
+// the language.
+func LocaleDayName(locale string, day time.Weekday, width NameWidth) (string, bool) {
+	names, ok := localeNames(locale)
+	if !ok || width < Wide || width > Narrow {
+		return "", false
+	}
+	return names.days[width][day], true
+}
+
+// LocaleMonthName finds the name of a month in the language of a locale,
+// like "nb_NO" or "ar". Returns false if there is no CLDR data for the language.
+func LocaleMonthName(locale string, month time.Month, width NameWidth) (string, bool) {
+	names, ok := localeNames(locale)
+	if !ok || width < Wide || width > Narrow {
+		return "", false
+	}
+	// There are no short names for months
+	i := 0
+	switch width {
+	case Abbreviated, Short:
+		i = 1
+	case Narrow:
+		i = 2
+	}
+	return names.months[i][month-1], true
+}
+
+// Find the CLDR week data for a region, or for the world if the region is unknown
+func regionWeek(region string) cldrWeek {
+	if w, ok := cldrWeeks[region]; ok {
+		return w
+	}
+	return cldrWeeks["001"]
+}
+
+// The days from start to end, both included, wrapping around the week
+func (w cldrWeek) weekend() []time.Weekday {
+	days := []time.Weekday{w.weekendStart}
+	for day := w.weekendStart; day != w.weekendEnd; {
+		day = (day + 1) % 7
+		days = append(days, day)
+	}
+	return days
+}
+
+// LocaleWeekData finds how the weeks are counted in the region of a locale,
+// like "en_US" or "ar-EG". The week data for the world is returned if the
+// locale has no region, or if there is no CLDR data for the region.
+func LocaleWeekData(locale string) WeekData {
+	l, _ := ParseLocale(locale)
+	w := regionWeek(l.Region)
+	return WeekData{w.firstDay, w.minDays, w.weekend()}
+}
+
+// firstWeekdayer is implemented by calendars that take the first day of the
+// week from the week data of their region
+type firstWeekdayer interface {
+	firstWeekday() time.Weekday
+}
+
+// FirstWeekday finds the first day of the week in the given calendar. For
+// calendars without week data, the week starts on Monday if MondayFirst
+// returns true, or else on Sunday.
+func FirstWeekday(cal Calendar) time.Weekday {
+	if f, ok := cal.(firstWeekdayer); ok {
+		return f.firstWeekday()
+	}
+	if cal.MondayFirst() {
+		return time.Monday
+	}
+	return time.Sunday
+}
+
+// Make a catalog from the CLDR names of a language, with the English words
+// for the rest
+func cldrCatalog(language string) (*Catalog, bool) {
+	names, ok := cldrLanguages[language]
+	if !ok {
+		return nil, false
+	}
+	return &Catalog{
+		Language:  language,
+		Days:      names.days[Wide],
+		Months:    names.months[Wide],
+		NormalDay: englishCatalog.NormalDay,
+		FlagDay:   englishCatalog.FlagDay,
+		Names:     map[string]string{},
+	}, true
+}
+
+// Find a short name for a day of the week, from the CLDR data for the
+// language of the calendar. This is the short name, like "Mo", if it has at
+// most two letters, or else the narrow name, like "M".
+func shortDayName(cal Calendar, day time.Weekday) string {
+	if names, ok := cldrLanguages[CalendarCatalog(cal).Language]; ok {
+		if name := strings.TrimSuffix(names.days[Short][day], "."); len([]rune(name)) <= 2 {
+			return name
+		}
+		return names.days[Narrow][day]
+	}
+	return string([]rune(cal.DayName(day))[:2])
+}
@@ -0,0 +1,172 @@
+//go:build ignore
+
+// Generates cldr_tables.go from the CLDR JSON files in the cldr directory
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The days of the week, as they are named in the CLDR data
+var cldrDays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// The weekdays, as they are named in the time package
+var weekdays = map[string]string{
+	"sun": "time.Sunday",
+	"mon": "time.Monday",
+	"tue": "time.Tuesday",
+	"wed": "time.Wednesday",
+	"thu": "time.Thursday",
+	"fri": "time.Friday",
+	"sat": "time.Saturday",
+}
+
+// The names in a ca-gregorian.json file, by width
+type gregorian struct {
+	Main map[string]struct {
+		Dates struct {
+			Calendars struct {
+				Gregorian struct {
+					Months struct {
+						Format map[string]map[string]string `json:"format"`
+					} `json:"months"`
+					Days struct {
+						Format map[string]map[string]string `json:"format"`
+					} `json:"days"`
+				} `json:"gregorian"`
+			} `json:"calendars"`
+		} `json:"dates"`
+	} `json:"main"`
+}
+
+// The fields of the weekData.json file, by region
+type weekData struct {
+	Supplemental struct {
+		WeekData struct {
+			MinDays      map[string]string `json:"minDays"`
+			FirstDay     map[string]string `json:"firstDay"`
+			WeekendStart map[string]string `json:"weekendStart"`
+			WeekendEnd   map[string]string `json:"weekendEnd"`
+		} `json:"weekData"`
+	} `json:"supplemental"`
+}
+
+// Read a JSON file into v
+func readJSON(filename string, v interface{}) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		log.Fatalln(filename+":", err)
+	}
+}
+
+// Write the names of the given width, in the given order, as a Go array
+func names(filename string, byKey map[string]string, keys []string) string {
+	quoted := make([]string, len(keys))
+	for i, key := range keys {
+		name, ok := byKey[key]
+		if !ok {
+			log.Fatalf("%s: missing %s", filename, key)
+		}
+		quoted[i] = strconv.Quote(name)
+	}
+	return "{" + strings.Join(quoted, ", ") + "}"
+}
+
+func main() {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by go run cldr_gen.go; DO NOT EDIT.\n\n")
+	buf.WriteString("package kal\n\nimport \"time\"\n\n")
+
+	// The names, by language
+	filenames, err := filepath.Glob(filepath.Join("cldr", "cldr-dates-modern", "main", "*", "ca-gregorian.json"))
+	if err != nil || len(filenames) == 0 {
+		log.Fatalln("no ca-gregorian.json files found")
+	}
+	sort.Strings(filenames)
+	months := make([]string, 12)
+	for i := range months {
+		months[i] = strconv.Itoa(i + 1)
+	}
+	buf.WriteString("// The names of the days and the months, by language\n")
+	buf.WriteString("var cldrLanguages = map[string]*cldrNames{\n")
+	for _, filename := range filenames {
+		var g gregorian
+		readJSON(filename, &g)
+		for language, m := range g.Main {
+			days := m.Dates.Calendars.Gregorian.Days.Format
+			monthNames := m.Dates.Calendars.Gregorian.Months.Format
+			fmt.Fprintf(&buf, "%q: {\n", language)
+			fmt.Fprintf(&buf, "days: [4][7]string{%s, %s, %s, %s},\n",
+				names(filename, days["wide"], cldrDays), names(filename, days["abbreviated"], cldrDays),
+				names(filename, days["short"], cldrDays), names(filename, days["narrow"], cldrDays))
+			fmt.Fprintf(&buf, "months: [3][12]string{%s, %s, %s},\n",
+				names(filename, monthNames["wide"], months), names(filename, monthNames["abbreviated"], months),
+				names(filename, monthNames["narrow"], months))
+			buf.WriteString("},\n")
+		}
+	}
+	buf.WriteString("}\n\n")
+
+	// The week data, by region
+	var w weekData
+	readJSON(filepath.Join("cldr", "cldr-core", "supplemental", "weekData.json"), &w)
+	wd := w.Supplemental.WeekData
+	regions := make(map[string]bool)
+	for _, m := range []map[string]string{wd.MinDays, wd.FirstDay, wd.WeekendStart, wd.WeekendEnd} {
+		for region := range m {
+			// Leave out the variants, like "GB-alt-variant"
+			if !strings.Contains(region, "-") {
+				regions[region] = true
+			}
+		}
+	}
+	sorted := make([]string, 0, len(regions))
+	for region := range regions {
+		sorted = append(sorted, region)
+	}
+	sort.Strings(sorted)
+	field := func(m map[string]string, region string) string {
+		if v, ok := m[region]; ok {
+			return v
+		}
+		return m["001"]
+	}
+	buf.WriteString("// The week data, by region, where \"001\" is the world\n")
+	buf.WriteString("var cldrWeeks = map[string]cldrWeek{\n")
+	for _, region := range sorted {
+		var days [3]string
+		for i, m := range []map[string]string{wd.FirstDay, wd.WeekendStart, wd.WeekendEnd} {
+			day, ok := weekdays[field(m, region)]
+			if !ok {
+				log.Fatalf("weekData.json: invalid day for %s: %q", region, field(m, region))
+			}
+			days[i] = day
+		}
+		minDays, err := strconv.Atoi(field(wd.MinDays, region))
+		if err != nil {
+			log.Fatalf("weekData.json: invalid minDays for %s: %v", region, err)
+		}
+		fmt.Fprintf(&buf, "%q: {%s, %d, %s, %s},\n", region, days[0], minDays, days[1], days[2])
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if err := os.WriteFile("cldr_tables.go", src, 0o644); err != nil {
+		log.Fatalln(err)
+	}
+}
@@ -0,0 +1,150 @@
+// Code generated by go run cldr_gen.go; DO NOT EDIT.
+
+package kal
+
+import "time"
+
+// The names of the days and the months, by language
+var cldrLanguages = map[string]*cldrNames{
+	"ar": {
+		days:   [4][7]string{{"الأحد", "الاثنين", "الثلاثاء", "الأربعاء", "الخميس", "الجمعة", "السبت"}, {"الأحد", "الاثنين", "الثلاثاء", "الأربعاء", "الخميس", "الجمعة", "السبت"}, {"أحد", "إثنين", "ثلاثاء", "أربعاء", "خميس", "جمعة", "سبت"}, {"ح", "ن", "ث", "ر", "خ", "ج", "س"}},
+		months: [3][12]string{{"يناير", "فبراير", "مارس", "أبريل", "مايو", "يونيو", "يوليو", "أغسطس", "سبتمبر", "أكتوبر", "نوفمبر", "ديسمبر"}, {"يناير", "فبراير", "مارس", "أبريل", "مايو", "يونيو", "يوليو", "أغسطس", "سبتمبر", "أكتوبر", "نوفمبر", "ديسمبر"}, {"ي", "ف", "م", "أ", "و", "ن", "ل", "غ", "س", "ك", "ب", "د"}},
+	},
+	"de": {
+		days:   [4][7]string{{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"}, {"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."}, {"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."}, {"S", "M", "D", "M", "D", "F", "S"}},
+		months: [3][12]string{{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"}, {"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."}, {"J", "F", "M", "A", "M", "J", "J", "A", "S", "O", "N", "D"}},
+	},
+	"en": {
+		days:   [4][7]string{{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}, {"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}, {"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"}, {"S", "M", "T", "W", "T", "F", "S"}},
+		months: [3][12]string{{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}, {"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}, {"J", "F", "M", "A", "M", "J", "J", "A", "S", "O", "N", "D"}},
+	},
+	"nb": {
+		days:   [4][7]string{{"søndag", "mandag", "tirsdag", "onsdag", "torsdag", "fredag", "lørdag"}, {"søn.", "man.", "tir.", "ons.", "tor.", "fre.", "lør."}, {"sø.", "ma.", "ti.", "on.", "to.", "fr.", "lø."}, {"S", "M", "T", "O", "T", "F", "L"}},
+		months: [3][12]string{{"januar", "februar", "mars", "april", "mai", "juni", "juli", "august", "september", "oktober", "november", "desember"}, {"jan.", "feb.", "mar.", "apr.", "mai", "jun.", "jul.", "aug.", "sep.", "okt.", "nov.", "des."}, {"J", "F", "M", "A", "M", "J", "J", "A", "S", "O", "N", "D"}},
+	},
+	"tr": {
+		days:   [4][7]string{{"Pazar", "Pazartesi", "Salı", "Çarşamba", "Perşembe", "Cuma", "Cumartesi"}, {"Paz", "Pzt", "Sal", "Çar", "Per", "Cum", "Cmt"}, {"Pa", "Pt", "Sa", "Ça", "Pe", "Cu", "Ct"}, {"P", "P", "S", "Ç", "P", "C", "C"}},
+		months: [3][12]string{{"Ocak", "Şubat", "Mart", "Nisan", "Mayıs", "Haziran", "Temmuz", "Ağustos", "Eylül", "Ekim", "Kasım", "Aralık"}, {"Oca", "Şub", "Mar", "Nis", "May", "Haz", "Tem", "Ağu", "Eyl", "Eki", "Kas", "Ara"}, {"O", "Ş", "M", "N", "M", "H", "T", "A", "E", "E", "K", "A"}},
+	},
+}
+
+// The week data, by region, where "001" is the world
+var cldrWeeks = map[string]cldrWeek{
+	"001": {time.Monday, 1, time.Saturday, time.Sunday},
+	"AD":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"AE":  {time.Saturday, 1, time.Friday, time.Saturday},
+	"AF":  {time.Saturday, 1, time.Thursday, time.Friday},
+	"AG":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"AN":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"AS":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"AT":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"AX":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"BD":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"BE":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"BG":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"BH":  {time.Saturday, 1, time.Friday, time.Saturday},
+	"BR":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"BS":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"BT":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"BW":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"BZ":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"CA":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"CH":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"CN":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"CO":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"CZ":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"DE":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"DJ":  {time.Saturday, 1, time.Saturday, time.Sunday},
+	"DK":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"DM":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"DO":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"DZ":  {time.Saturday, 1, time.Friday, time.Saturday},
+	"EE":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"EG":  {time.Saturday, 1, time.Friday, time.Saturday},
+	"ES":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"ET":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"FI":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"FJ":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"FO":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"FR":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"GB":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"GF":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"GG":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"GI":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"GP":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"GR":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"GT":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"GU":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"HK":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"HN":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"HU":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"ID":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"IE":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"IL":  {time.Sunday, 1, time.Friday, time.Saturday},
+	"IM":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"IN":  {time.Sunday, 1, time.Sunday, time.Sunday},
+	"IQ":  {time.Saturday, 1, time.Friday, time.Saturday},
+	"IR":  {time.Saturday, 1, time.Friday, time.Friday},
+	"IS":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"IT":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"JE":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"JM":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"JO":  {time.Saturday, 1, time.Friday, time.Saturday},
+	"JP":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"KE":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"KH":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"KR":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"KW":  {time.Saturday, 1, time.Friday, time.Saturday},
+	"LA":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"LI":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"LT":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"LU":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"LY":  {time.Saturday, 1, time.Friday, time.Saturday},
+	"MC":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"MH":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"MM":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"MO":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"MQ":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"MT":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"MV":  {time.Friday, 1, time.Saturday, time.Sunday},
+	"MX":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"MZ":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"NI":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"NL":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"NO":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"NP":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"OM":  {time.Saturday, 1, time.Friday, time.Saturday},
+	"PA":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"PE":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"PH":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"PK":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"PL":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"PR":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"PT":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"PY":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"QA":  {time.Saturday, 1, time.Friday, time.Saturday},
+	"RE":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"RU":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"SA":  {time.Sunday, 1, time.Friday, time.Saturday},
+	"SD":  {time.Saturday, 1, time.Friday, time.Saturday},
+	"SE":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"SG":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"SJ":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"SK":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"SM":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"SV":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"SY":  {time.Saturday, 1, time.Friday, time.Saturday},
+	"TH":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"TT":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"TW":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"UG":  {time.Monday, 1, time.Sunday, time.Sunday},
+	"UM":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"US":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"VA":  {time.Monday, 4, time.Saturday, time.Sunday},
+	"VE":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"VI":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"WS":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"YE":  {time.Sunday, 1, time.Friday, time.Saturday},
+	"ZA":  {time.Sunday, 1, time.Saturday, time.Sunday},
+	"ZW":  {time.Sunday, 1, time.Saturday, time.Sunday},
+}
@@ -0,0 +1,57 @@
+package kal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCLDR(t *testing.T) {
+	if name, ok := LocaleDayName("ar_EG", time.Friday, Narrow); !ok || name != "ج" {
+		t.Errorf("narrow Friday in Arabic: got %q, %v", name, ok)
+	}
+	if name, ok := LocaleMonthName("nb-NO", time.March, Abbreviated); !ok || name != "mar." {
+		t.Errorf("abbreviated March in Norwegian: got %q, %v", name, ok)
+	}
+	if _, ok := LocaleDayName("xx", time.Monday, Wide); ok {
+		t.Error("got a name for an unknown language")
+	}
+
+	for locale, want := range map[string]WeekData{
+		"nb_NO": {time.Monday, 4, []time.Weekday{time.Saturday, time.Sunday}},
+		"en_US": {time.Sunday, 1, []time.Weekday{time.Saturday, time.Sunday}},
+		"ar-EG": {time.Saturday, 1, []time.Weekday{time.Friday, time.Saturday}},
+		"fa_AF": {time.Saturday, 1, []time.Weekday{time.Thursday, time.Friday}},
+		"en":    {time.Monday, 1, []time.Weekday{time.Saturday, time.Sunday}},
+	} {
+		if got := LocaleWeekData(locale); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %+v, want %+v", locale, got, want)
+		}
+	}
+
+	// Pazar and Pazartesi, and Cuma and Cumartesi, have different short names
+	if got := TwoLetterDays(NewTRCalendar(), true); got != "Pt Sa Ça Pe Cu Ct Pa" {
+		t.Errorf("Turkish days: got %q", got)
+	}
+
+	// German names of days and months, with the Norwegian red days
+	cal, err := NewCalendar("de_NO", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cal.DayName(time.Monday) != "Montag" || !cal.MondayFirst() || !Holiday(cal, time.Date(2025, time.May, 17, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("de_NO: got the wrong names or rules")
+	}
+
+	// The first day of the week is found through the cache and the weekend wrappers
+	us, err := NewCalendar("de_US", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := FirstWeekday(us); got != time.Sunday {
+		t.Errorf("de_US: got %s as the first day of the week", got)
+	}
+	if got := FirstWeekday(WithWeekend(cal, time.Friday, time.Saturday)); got != time.Monday {
+		t.Errorf("de_NO with another weekend: got %s as the first day of the week", got)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/xyproto/kal"
+)
+
+// parseDate parses a date on the form YYYY-MM-DD
+func parseDate(s string) (time.Time, error) {
+	return time.Parse("2006-01-02", s)
+}
+
+// dimDate writes a date dimension table for the given calendar to stdout.
+// The arguments are the flags, optionally followed by a year.
+func dimDate(cal kal.Calendar, args []string) error {
+	fs := flag.NewFlagSet("dimdate", flag.ExitOnError)
+	format := fs.String("format", "csv", "output format: csv, jsonl or sql")
+	table := fs.String("table", "dim_date", "table name for the sql format")
+	fromFlag := fs.String("from", "", "first date, YYYY-MM-DD")
+	toFlag := fs.String("to", "", "last date, YYYY-MM-DD")
+	fs.Parse(args)
+
+	// Default to the current year, or to the given year
+	year := time.Now().Year()
+	if fs.NArg() > 0 {
+		y, err := strconv.Atoi(fs.Arg(0))
+		if err != nil {
+			return errors.New("invalid year: " + fs.Arg(0))
+		}
+		year = y
+	}
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	var err error
+	if *fromFlag != "" {
+		if from, err = parseDate(*fromFlag); err != nil {
+			return err
+		}
+	}
+	if *toFlag != "" {
+		if to, err = parseDate(*toFlag); err != nil {
+			return err
+		}
+	}
+
+	rows := kal.DateDimensions(cal, from, to)
+	switch *format {
+	case "csv":
+		return kal.WriteDateDimensionsCSV(os.Stdout, rows)
+	case "jsonl", "json":
+		return kal.WriteDateDimensionsJSONLines(os.Stdout, rows)
+	case "sql":
+		return kal.WriteDateDimensionsSQL(os.Stdout, *table, rows)
+	}
+	return errors.New("unsupported format: " + *format)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/xyproto/kal"
+)
+
+// ics writes the red days and notable days of a year as an iCalendar file
+// to stdout. The arguments are the flags, optionally followed by a locale,
+// like nb_NO, and a year.
+func ics(cal kal.Calendar, args []string) error {
+	fs := flag.NewFlagSet("ics", flag.ExitOnError)
+	name := fs.String("name", "", "name of the calendar")
+	domain := fs.String("domain", "kal", "domain part of the event UIDs")
+	redOnly := fs.Bool("red", false, "only include the red days")
+	fs.Parse(args)
+
+	rest := fs.Args()
+	if len(rest) > 0 {
+		if _, err := strconv.Atoi(rest[0]); err != nil {
+			c, err := kal.NewCalendar(rest[0], true)
+			if err != nil {
+				return err
+			}
+			cal = c
+			rest = rest[1:]
+		}
+	}
+	year := time.Now().Year()
+	if len(rest) > 0 {
+		y, err := strconv.Atoi(rest[0])
+		if err != nil {
+			return errors.New("invalid year: " + rest[0])
+		}
+		year = y
+	}
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	return kal.WriteICS(os.Stdout, cal, from, to, kal.ICSOptions{Name: *name, Domain: *domain, RedOnly: *redOnly})
+}
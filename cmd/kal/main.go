@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -40,19 +41,25 @@ func centeredMonthYearString(cal kal.Calendar, year int, month time.Month, width
 	return centerPad(s, width)
 }
 
-func weekdayPosition(mondayFirst bool, current time.Time) int {
-	weekdayPosition := int(current.Weekday())
-	if mondayFirst {
-		weekdayPosition--
-		if weekdayPosition < 0 {
-			weekdayPosition = 6
-		}
+// The number of days from the first day of the week to the weekday of current
+func weekdayPosition(first time.Weekday, current time.Time) int {
+	return (int(current.Weekday()) - int(first) + 7) % 7
+}
+
+// firstWeekday finds the first day of the week from the region of the given
+// locale, like Saturday for "ar_EG", since the calendar may be made for
+// another region. Without a region, the calendar decides.
+func firstWeekday(cal kal.Calendar, locale string) time.Weekday {
+	if l, err := kal.ParseLocale(locale); err == nil && l.Region != "" {
+		return kal.LocaleWeekData(locale).FirstDay
 	}
-	return weekdayPosition
+	return kal.FirstWeekday(cal)
 }
 
-// MonthCalendar returns a string that is a complete overview of the given month
-func MonthCalendar(cal *kal.Calendar, givenYear int, givenMonth time.Month) string {
+// MonthCalendar returns a string that is a complete overview of the given month.
+// The weeks start on the given weekday. If fiscal is not nil, the week is
+// labeled with the fiscal week instead of the ISO week.
+func MonthCalendar(cal *kal.Calendar, givenYear int, givenMonth time.Month, first time.Weekday, fiscal *kal.FiscalCalendar) string {
 
 	mondayFirst := (*cal).MondayFirst()
 
@@ -64,33 +71,64 @@ func MonthCalendar(cal *kal.Calendar, givenYear int, givenMonth time.Month) stri
 	// Add the week, if this is the current month
 	var weekString string
 	if now.Month() == givenMonth && now.Year() == givenYear {
-		_, w := now.ISOWeek()
-		weekString = "w" + strconv.Itoa(w)
+		if fiscal != nil {
+			weekString = "fw" + strconv.Itoa(fiscal.Date(now).Week)
+		} else {
+			_, w := now.ISOWeek()
+			weekString = "w" + strconv.Itoa(w)
+		}
 	}
 
 	// Month and year, centered
 	sb.WriteString("<lightblue>" + centeredMonthYearString(*cal, givenYear, givenMonth, 20-len(weekString)) + "</lightblue><darkgray>" + weekString + "</darkgray>\n")
 
-	// The shortened names of the week days
-	sb.WriteString("<white>" + kal.TwoLetterDays(*cal, (*cal).MondayFirst()) + "</white>\n")
+	// The shortened names of the week days, from the first day of the week, with the weekend in red
+	weekdays := strings.Fields(kal.TwoLetterDays(*cal, false))
+	for i := 0; i < 7; i++ {
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+		weekday := (first + time.Weekday(i)) % 7
+		// The names may be shorter than two letters, like "M"
+		dayName := rightPad(weekdays[weekday], 2)
+		// A date in the same week as the first day of the month, with the given weekday
+		if kal.WeekendDay(*cal, current.AddDate(0, 0, int(weekday)-int(current.Weekday()))) {
+			sb.WriteString("<red>" + dayName + "</red>")
+		} else {
+			sb.WriteString("<white>" + dayName + "</white>")
+		}
+	}
+	sb.WriteString("\n")
 
 	// Indentation before the first day of the month
-	sb.WriteString(strings.Repeat(" ", weekdayPosition(mondayFirst, current)*3))
+	sb.WriteString(strings.Repeat(" ", weekdayPosition(first, current)*3))
+
+	// The word for flag flying days, in the language of the calendar
+	flagDay := kal.CalendarCatalog(*cal).FlagDay
 
 	// Output all the numbers of the month, with linebreaks at appropriate locations
 	for current.Month() == givenMonth {
 		isFlagDay := kal.FlagDay(*cal, current)
 		if current.Day() == now.Day() && current.Month() == now.Month() && current.Year() == now.Year() { // Today
 			sb.WriteString(fmt.Sprintf(vt.BackgroundBlue.String()+"<lightyellow>%2d</lightyellow> ", current.Day()))
-		} else if isRedDay := kal.RedDay(*cal, current); current.Weekday() == time.Sunday || isRedDay { // Red day
+		} else if isHalfDay, start := kal.HalfDay(*cal, current); isHalfDay && !kal.WeekendDay(*cal, current) { // Half day
+			sb.WriteString(fmt.Sprintf("<magenta>%2d</magenta> ", current.Day()))
+			// Collect descriptions, with the time of day when the time off starts
+			startTime := current.Add(start).Format("15:04")
+			if mondayFirst {
+				descriptions.WriteString(fmt.Sprintf("<magenta>%2d. %s</magenta> - %s (%s–)\n", current.Day(), (*cal).MonthName(givenMonth), kal.Describe(*cal, current), startTime))
+			} else {
+				descriptions.WriteString(fmt.Sprintf("<magenta>%s %d</magenta> - %s (%s–)\n", (*cal).MonthName(givenMonth), current.Day(), kal.Describe(*cal, current), startTime))
+			}
+		} else if isHoliday := kal.Holiday(*cal, current); isHoliday || kal.WeekendDay(*cal, current) { // Red day
 			sb.WriteString(fmt.Sprintf("<red>%2d</red> ", current.Day()))
-			// Collect descriptions, then print them below
-			if isRedDay {
+			// Collect descriptions of public holidays, then print them below
+			if isHoliday {
 				if isFlagDay {
 					if mondayFirst {
-						descriptions.WriteString(fmt.Sprintf("<lightblue>%2d. %s</lightblue> - %s (flaggdag)\n", current.Day(), (*cal).MonthName(givenMonth), kal.Describe(*cal, current)))
+						descriptions.WriteString(fmt.Sprintf("<lightblue>%2d. %s</lightblue> - %s (%s)\n", current.Day(), (*cal).MonthName(givenMonth), kal.Describe(*cal, current), flagDay))
 					} else {
-						descriptions.WriteString(fmt.Sprintf("<lightblue>%s %d</lightblue> - %s (flaggdag)\n", (*cal).MonthName(givenMonth), current.Day(), kal.Describe(*cal, current)))
+						descriptions.WriteString(fmt.Sprintf("<lightblue>%s %d</lightblue> - %s (%s)\n", (*cal).MonthName(givenMonth), current.Day(), kal.Describe(*cal, current), flagDay))
 					}
 				} else {
 					if mondayFirst {
@@ -104,9 +142,9 @@ func MonthCalendar(cal *kal.Calendar, givenYear int, givenMonth time.Month) stri
 			sb.WriteString(fmt.Sprintf("<lightblue>%2d</lightblue> ", current.Day()))
 			// Collect descriptions, then print them below
 			if mondayFirst {
-				descriptions.WriteString(fmt.Sprintf("<lightblue>%2d. %s</lightblue> - %s (flaggdag)\n", current.Day(), (*cal).MonthName(givenMonth), kal.Describe(*cal, current)))
+				descriptions.WriteString(fmt.Sprintf("<lightblue>%2d. %s</lightblue> - %s (%s)\n", current.Day(), (*cal).MonthName(givenMonth), kal.Describe(*cal, current), flagDay))
 			} else {
-				descriptions.WriteString(fmt.Sprintf("<lightblue>%s %d</lightblue> - %s (flaggdag)\n", (*cal).MonthName(givenMonth), current.Day(), kal.Describe(*cal, current)))
+				descriptions.WriteString(fmt.Sprintf("<lightblue>%s %d</lightblue> - %s (%s)\n", (*cal).MonthName(givenMonth), current.Day(), kal.Describe(*cal, current), flagDay))
 			}
 		} else { // Ordinary day
 			sb.WriteString(fmt.Sprintf("%2d ", current.Day()))
@@ -114,7 +152,7 @@ func MonthCalendar(cal *kal.Calendar, givenYear int, givenMonth time.Month) stri
 
 		current = current.AddDate(0, 0, 1)
 
-		if (mondayFirst && (current.Weekday() == time.Monday)) || (!mondayFirst && (current.Weekday() == time.Sunday)) {
+		if current.Weekday() == first {
 			sb.WriteString("\n")
 		}
 	}
@@ -131,22 +169,93 @@ func MonthCalendar(cal *kal.Calendar, givenYear int, givenMonth time.Month) stri
 	return calendarString
 }
 
+// fiscalPatterns are the patterns that can be given with the -fiscal flag
+var fiscalPatterns = map[string]kal.FiscalPattern{
+	"445": kal.Pattern445,
+	"454": kal.Pattern454,
+	"544": kal.Pattern544,
+}
+
 func main() {
+	fiscalFlag := flag.String("fiscal", "", "label the week with the fiscal week of a retail calendar (445, 454 or 544)")
+	flag.Parse()
+	args := append([]string{os.Args[0]}, flag.Args()...)
+
+	var fiscal *kal.FiscalCalendar
+	if *fiscalFlag != "" {
+		pattern, ok := fiscalPatterns[strings.ReplaceAll(*fiscalFlag, "-", "")]
+		if !ok {
+			log.Fatalln("unsupported fiscal pattern: " + *fiscalFlag)
+		}
+		fc := kal.NewRetailFiscalCalendar()
+		fc.Pattern = pattern
+		fiscal = &fc
+	}
+
+	// The locale for dates is given by LC_ALL, LC_TIME or LANG, in that order
+	langEnv := env.Str("LC_ALL", env.Str("LC_TIME", env.Str("LANG")))
+	locale, err := kal.MatchLocale(langEnv)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "kal: "+err.Error()+", using "+kal.DefaultLocale)
+		locale = kal.DefaultLocale
+	}
+
+	cal, err := kal.NewCalendar(locale, true)
+	if err != nil {
+		log.Fatalln("could not create a calendar using locale " + locale)
+	}
+
+	// Subcommands
+	if len(args) > 1 {
+		switch args[1] {
+		case "dimdate":
+			if err := dimDate(cal, args[2:]); err != nil {
+				log.Fatalln(err)
+			}
+			return
+		case "plan":
+			if err := plan(cal, args[2:]); err != nil {
+				log.Fatalln(err)
+			}
+			return
+		case "overlap":
+			if err := overlap(args[2:]); err != nil {
+				log.Fatalln(err)
+			}
+			return
+		case "ics":
+			if err := ics(cal, args[2:]); err != nil {
+				log.Fatalln(err)
+			}
+			return
+		case "serve":
+			if err := serve(args[2:]); err != nil {
+				log.Fatalln(err)
+			}
+			return
+		case "rotation", "turnus":
+			if err := rotation(cal, firstWeekday(cal, langEnv), args[2:]); err != nil {
+				log.Fatalln(err)
+			}
+			return
+		}
+	}
+
 	now := time.Now()
 
 	currentYear := now.Year()
 	currentMonth := now.Month()
 
 	// Check if the first given argument is a number. If yes, use that as the current year.
-	if len(os.Args) > 2 {
-		if m, err := strconv.Atoi(os.Args[1]); err == nil && m >= 1 && m <= 12 { // success
+	if len(args) > 2 {
+		if m, err := strconv.Atoi(args[1]); err == nil && m >= 1 && m <= 12 { // success
 			currentMonth = time.Month(m)
 		}
-		if y, err := strconv.Atoi(os.Args[2]); err == nil { // success
+		if y, err := strconv.Atoi(args[2]); err == nil { // success
 			currentYear = y
 		}
-	} else if len(os.Args) > 1 {
-		if y, err := strconv.Atoi(os.Args[1]); err == nil { // success
+	} else if len(args) > 1 {
+		if y, err := strconv.Atoi(args[1]); err == nil { // success
 			currentYear = y
 		}
 		// Assume that a single argument <= 12 was intended to be a month
@@ -156,17 +265,7 @@ func main() {
 		}
 	}
 
-	langEnv := strings.TrimSuffix(env.Str("LC_ALL", env.Str("LANG")), ".UTF-8")
-	if langEnv == "C" || env.Str("LC_ALL") == "C" {
-		langEnv = "en_US" // default to en_US
-	}
-
-	cal, err := kal.NewCalendar(langEnv, true)
-	if err != nil {
-		log.Fatalln("could not create a calendar using locale " + langEnv)
-	}
-
-	moCal := MonthCalendar(&cal, currentYear, currentMonth)
+	moCal := MonthCalendar(&cal, currentYear, currentMonth, firstWeekday(cal, langEnv), fiscal)
 
 	vt.New().Print(moCal)
 }
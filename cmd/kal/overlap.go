@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xyproto/kal"
+	"github.com/xyproto/vt"
+)
+
+// timeZones are the time zones that are used for the supported regions
+var timeZones = map[string]string{
+	"NO": "Europe/Oslo",
+	"US": "America/New_York",
+	"TR": "Europe/Istanbul",
+}
+
+// parseHours parses opening hours on the form 09-17 or 08:30-16:00
+func parseHours(s string) (time.Duration, time.Duration, error) {
+	fields := strings.SplitN(s, "-", 2)
+	if len(fields) != 2 {
+		return 0, 0, errors.New("invalid hours: " + s)
+	}
+	var tods [2]time.Duration
+	for i, field := range fields {
+		if !strings.Contains(field, ":") {
+			field += ":00"
+		}
+		t, err := time.Parse("15:04", field)
+		if err != nil {
+			return 0, 0, errors.New("invalid hours: " + s)
+		}
+		tods[i] = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	return tods[0], tods[1], nil
+}
+
+// overlap lists the days in a month or a year that are business days in
+// all the given locales, and the working hours they have in common.
+// For the other days, it lists which locales have a day off, and why.
+// The arguments are the flags, followed by the locales and YYYY-MM or YYYY.
+func overlap(args []string) error {
+	fs := flag.NewFlagSet("overlap", flag.ExitOnError)
+	hoursFlag := fs.String("hours", "09-17", "working hours, in the local time of each locale")
+	fs.Parse(args)
+	if fs.NArg() < 2 {
+		return errors.New("usage: kal overlap [-hours 09-17] LOCALE... YYYY-MM")
+	}
+	open, close, err := parseHours(*hoursFlag)
+	if err != nil {
+		return err
+	}
+
+	// The last argument is the month or the year
+	period := fs.Arg(fs.NArg() - 1)
+	from, err := time.Parse("2006-01", period)
+	to := from.AddDate(0, 1, -1)
+	if err != nil {
+		if from, err = time.Parse("2006", period); err != nil {
+			return errors.New("invalid month: " + period)
+		}
+		to = from.AddDate(1, 0, -1)
+	}
+
+	locales := fs.Args()[:fs.NArg()-1]
+	cals := make([]kal.Calendar, len(locales))
+	bhs := make([]kal.BusinessHours, len(locales))
+	for i, arg := range locales {
+		locale, err := kal.MatchLocale(arg)
+		if err != nil {
+			return err
+		}
+		if cals[i], err = kal.NewCalendar(locale, true); err != nil {
+			return err
+		}
+		loc := time.UTC
+		_, region, _ := strings.Cut(locale, "_")
+		if name, ok := timeZones[region]; ok {
+			if l, err := time.LoadLocation(name); err == nil {
+				loc = l
+			}
+		}
+		bhs[i] = kal.NewBusinessHours(cals[i], kal.WorkWeek(open, close), loc)
+	}
+
+	var sb strings.Builder
+	for _, a := range kal.CalendarAvailability(from, to, cals...) {
+		date := a.Date.Format("2006-01-02")
+		if a.Common() {
+			if start, end, ok := kal.OverlappingHours(a.Date, bhs...); ok {
+				sb.WriteString(fmt.Sprintf("<white>%s</white> %s–%s UTC\n", date, start.UTC().Format("15:04"), end.UTC().Format("15:04")))
+			} else {
+				sb.WriteString(fmt.Sprintf("<white>%s</white> <darkgray>-</darkgray>\n", date))
+			}
+			continue
+		}
+		var off []string
+		for _, dayOff := range a.Off {
+			off = append(off, locales[dayOff.Index]+": "+dayOff.Description)
+		}
+		sb.WriteString(fmt.Sprintf("<red>%s</red> %s\n", date, strings.Join(off, "; ")))
+	}
+	vt.New().Print(sb.String())
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xyproto/kal"
+	"github.com/xyproto/vt"
+)
+
+// shortDate formats a date as "17. april" or as "April 17", depending on the calendar
+func shortDate(cal kal.Calendar, date time.Time) string {
+	if cal.MondayFirst() {
+		return fmt.Sprintf("%d. %s", date.Day(), cal.MonthName(date.Month()))
+	}
+	return fmt.Sprintf("%s %d", cal.MonthName(date.Month()), date.Day())
+}
+
+// plan outputs the best placement of vacation days for a year, and the bridge days.
+// The arguments are the flags, optionally followed by a year.
+func plan(cal kal.Calendar, args []string) error {
+	fs := flag.NewFlagSet("plan", flag.ExitOnError)
+	days := fs.Int("days", 5, "number of vacation days")
+	minBlock := fs.Int("min", 0, "minimum number of consecutive days off in each block")
+	fs.Parse(args)
+
+	year := time.Now().Year()
+	if fs.NArg() > 0 {
+		y, err := strconv.Atoi(fs.Arg(0))
+		if err != nil {
+			return errors.New("invalid year: " + fs.Arg(0))
+		}
+		year = y
+	}
+
+	var sb strings.Builder
+	p := kal.PlanVacation(cal, year, *days, kal.PlanOptions{MinBlock: *minBlock})
+	for _, block := range p.Blocks {
+		var leave []string
+		for _, date := range block.Leave {
+			leave = append(leave, shortDate(cal, date))
+		}
+		sb.WriteString(fmt.Sprintf("<lightblue>%s – %s</lightblue> - %s (%s)\n", shortDate(cal, block.Start), shortDate(cal, block.End), kal.Period{Days: block.DaysOff}.Format(cal), strings.Join(leave, ", ")))
+	}
+	sb.WriteString(fmt.Sprintf("<white>%g → %s</white>\n", p.VacationDays, kal.Period{Days: p.DaysOff}.Format(cal)))
+
+	if bridgeDays := kal.BridgeDays(cal, year); len(bridgeDays) > 0 {
+		sb.WriteString("\n")
+		for _, date := range bridgeDays {
+			sb.WriteString(fmt.Sprintf("<magenta>%s</magenta> - %s\n", shortDate(cal, date), cal.DayName(date.Weekday())))
+		}
+	}
+	vt.New().Print(sb.String())
+	return nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xyproto/kal"
+	"github.com/xyproto/vt"
+)
+
+// parseShifts parses shifts on the form D=07-19,N=19-07
+func parseShifts(s string) ([]kal.Shift, error) {
+	var shifts []kal.Shift
+	for _, field := range strings.Split(s, ",") {
+		nameHours := strings.SplitN(field, "=", 2)
+		if len(nameHours) != 2 || nameHours[0] == "" {
+			return nil, errors.New("invalid shift: " + field)
+		}
+		start, end, err := parseHours(nameHours[1])
+		if err != nil {
+			return nil, err
+		}
+		shifts = append(shifts, kal.Shift{Name: nameHours[0], Start: start, End: end})
+	}
+	return shifts, nil
+}
+
+// RotationCalendar returns a month overview where every day is followed by
+// the letters of the teams that start a shift that day, in the order of the
+// shift start times. Days off are red, half days are magenta, and the
+// premium hours for each team are listed below. The weeks start on the given weekday.
+func RotationCalendar(cal kal.Calendar, r kal.Rotation, givenYear int, givenMonth time.Month, firstDay time.Weekday) string {
+	first := time.Date(givenYear, givenMonth, 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1)
+
+	// The width of each cell is the day number and the teams on duty
+	width := 0
+	for current := first; !current.After(last); current = current.AddDate(0, 0, 1) {
+		if n := len(r.OnDuty(current)); n > width {
+			width = n
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<lightblue>" + centerPad(fmt.Sprintf("%s %d", cal.MonthName(givenMonth), givenYear), 7*(width+3)-1) + "</lightblue>\n")
+	weekdays := strings.Fields(kal.TwoLetterDays(cal, false))
+	for i := 0; i < 7; i++ {
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString("<white>" + rightPad(weekdays[(firstDay+time.Weekday(i))%7], width+2) + "</white>")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat(" ", weekdayPosition(firstDay, first)*(width+3)))
+
+	for current := first; !current.After(last); current = current.AddDate(0, 0, 1) {
+		assignments := r.OnDuty(current)
+		sort.SliceStable(assignments, func(i, j int) bool { return assignments[i].Start.Before(assignments[j].Start) })
+		var letters string
+		for _, a := range assignments {
+			letters += a.Team
+		}
+		cell := rightPad(fmt.Sprintf("%2d", current.Day())+letters, width+2)
+		if half, _ := kal.HalfDay(cal, current); half && !kal.Holiday(cal, current) {
+			sb.WriteString("<magenta>" + cell + "</magenta> ")
+		} else if kal.Holiday(cal, current) || kal.WeekendDay(cal, current) {
+			sb.WriteString("<red>" + cell + "</red> ")
+		} else {
+			sb.WriteString(cell + " ")
+		}
+		next := current.AddDate(0, 0, 1)
+		if next.Weekday() == firstDay {
+			sb.WriteString("\n")
+		}
+	}
+	if !strings.HasSuffix(sb.String(), "\n") {
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	summaries := kal.SummarizePremiums(r.Assignments(first, last))
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Team < summaries[j].Team })
+	for _, s := range summaries {
+		sb.WriteString(fmt.Sprintf("<white>%s</white>: %d shifts, %gh, <magenta>%gh premium</magenta>\n", s.Team, s.Shifts, s.Hours.Hours(), s.PremiumHours.Hours()))
+	}
+	return sb.String()
+}
+
+// rotation shows a month with the teams on duty in a shift rotation.
+// The arguments are the flags, optionally followed by the month and the year.
+// The weeks start on firstDay.
+func rotation(cal kal.Calendar, firstDay time.Weekday, args []string) error {
+	fs := flag.NewFlagSet("rotation", flag.ExitOnError)
+	patternFlag := fs.String("pattern", "DD--DDD--DD---NN--NNN--NN---", "the shift pattern, one letter per day and - for a day off")
+	shiftsFlag := fs.String("shifts", "D=07-19,N=19-07", "the shifts in the pattern")
+	teams := fs.Int("teams", 4, "number of teams")
+	startFlag := fs.String("start", "2024-01-01", "the first day of the pattern, YYYY-MM-DD")
+	eveFlag := fs.String("eve", "15:00", "the time of day when the premium starts on half days")
+	fs.Parse(args)
+
+	shifts, err := parseShifts(*shiftsFlag)
+	if err != nil {
+		return err
+	}
+	pattern, err := kal.ParseShiftPattern(*patternFlag, shifts...)
+	if err != nil {
+		return err
+	}
+	start, err := parseDate(*startFlag)
+	if err != nil {
+		return err
+	}
+	eve, err := time.Parse("15:04", *eveFlag)
+	if err != nil {
+		return errors.New("invalid time of day: " + *eveFlag)
+	}
+	if *teams < 1 || *teams > 26 {
+		return errors.New("the number of teams must be from 1 to 26")
+	}
+	r := kal.NewRotation(cal, start, pattern, *teams)
+	r.EveStart = time.Duration(eve.Hour())*time.Hour + time.Duration(eve.Minute())*time.Minute
+
+	now := time.Now()
+	year, month := now.Year(), now.Month()
+	if fs.NArg() > 0 {
+		m, err := strconv.Atoi(fs.Arg(0))
+		if err != nil || m < 1 || m > 12 {
+			return errors.New("invalid month: " + fs.Arg(0))
+		}
+		month = time.Month(m)
+	}
+	if fs.NArg() > 1 {
+		if year, err = strconv.Atoi(fs.Arg(1)); err != nil {
+			return errors.New("invalid year: " + fs.Arg(1))
+		}
+	}
+
+	vt.New().Print(RotationCalendar(cal, r, year, month, firstDay))
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/xyproto/kal"
+	"github.com/xyproto/kal/api"
+	"github.com/xyproto/kal/caldav"
+)
+
+// The locales that are served over CalDAV if none are given
+var serveLocales = []string{"nb_NO", "en_US", "tr_TR"}
+
+// The number of years that are kept in the cache of each calendar served over CalDAV
+const serveCacheYears = 32
+
+// serve serves the JSON API, and the calendars for the given locales over
+// CalDAV, if an address is given for it. The arguments are the flags,
+// followed by the locales. If no address is given, the JSON API is served
+// on :8000.
+func serve(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", "", "address for serving the JSON API, like :8000")
+	caldavAddr := fs.String("caldav", "", "address for serving the calendars over CalDAV, like :8080")
+	fs.Parse(args)
+
+	if *addr == "" && *caldavAddr == "" {
+		*addr = ":8000"
+	}
+	errs := make(chan error, 2)
+	if *addr != "" {
+		log.Printf("serving the JSON API on %s", *addr)
+		go func() {
+			errs <- http.ListenAndServe(*addr, api.NewHandler())
+		}()
+	}
+	if *caldavAddr != "" {
+		locales := fs.Args()
+		if len(locales) == 0 {
+			locales = serveLocales
+		}
+		calendars := make(map[string]kal.Calendar)
+		for _, locale := range locales {
+			cal, err := kal.NewCalendar(locale, false)
+			if err != nil {
+				return err
+			}
+			calendars[locale] = kal.NewBoundedCachedCalendar(cal, serveCacheYears)
+		}
+		log.Printf("serving CalDAV on %s, for %s", *caldavAddr, strings.Join(locales, ", "))
+		go func() {
+			errs <- http.ListenAndServe(*caldavAddr, caldav.NewHandler(calendars))
+		}()
+	}
+	return <-errs
+}
@@ -0,0 +1,224 @@
+package kal
+
+// Date dimension tables, for populating a dim_date table in a data warehouse
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A DateDimension is a row in a date dimension table
+type DateDimension struct {
+	Date               time.Time
+	Year               int
+	Quarter            int // 1 to 4
+	Month              int
+	Day                int
+	DayOfYear          int
+	ISOYear            int // the year the ISO week belongs to
+	ISOWeek            int
+	Weekday            time.Weekday
+	WeekdayName        string // in the language of the calendar
+	MonthName          string // in the language of the calendar
+	RedDay             bool   // a public holiday or a weekend day
+	Holiday            bool
+	Weekend            bool
+	BusinessDay        bool
+	NotableDay         bool
+	FlagDay            bool
+	HalfDay            bool // as reported by Calendar.HalfDay, also on weekend days
+	HolidayName        string
+	NotableName        string
+	BusinessDayOfMonth int // 1 for the first business day of the month, 0 if this is not a business day
+	DaysToNextHoliday  int // -1 if no holiday is found within ten years
+}
+
+// The column names of a date dimension table, in the same order as the values
+var dateDimensionColumns = []string{
+	"date", "year", "quarter", "month", "day", "day_of_year", "iso_year", "iso_week",
+	"weekday", "weekday_name", "month_name", "red_day", "holiday", "weekend",
+	"business_day", "notable_day", "flag_day", "half_day", "holiday_name",
+	"notable_name", "business_day_of_month", "days_to_next_holiday",
+}
+
+// The values of a row, as strings, ints or bools
+func (dd DateDimension) values() []interface{} {
+	return []interface{}{
+		dd.Date.Format("2006-01-02"), dd.Year, dd.Quarter, dd.Month, dd.Day, dd.DayOfYear,
+		dd.ISOYear, dd.ISOWeek, int(dd.Weekday), dd.WeekdayName, dd.MonthName, dd.RedDay,
+		dd.Holiday, dd.Weekend, dd.BusinessDay, dd.NotableDay, dd.FlagDay, dd.HalfDay,
+		dd.HolidayName, dd.NotableName, dd.BusinessDayOfMonth, dd.DaysToNextHoliday,
+	}
+}
+
+// DateDimensions returns a date dimension row for every date from and
+// including the date of from, to and including the date of to
+func DateDimensions(cal Calendar, from, to time.Time) []DateDimension {
+	first, last := dateSpan(from, to, time.UTC)
+	if last.Before(first) {
+		return nil
+	}
+
+	// Find the first holiday after the last date, then go backwards
+	nextHoliday := time.Time{}
+	for i, current := 0, last.AddDate(0, 0, 1); i < maxSearchDays; i, current = i+1, current.AddDate(0, 0, 1) {
+		if Holiday(cal, current) {
+			nextHoliday = current
+			break
+		}
+	}
+	rows := make([]DateDimension, actualDays(first, last)+1)
+	for i := len(rows) - 1; i >= 0; i-- {
+		date := first.AddDate(0, 0, i)
+		isoYear, isoWeek := date.ISOWeek()
+		red, holidayName, redFlag := cal.RedDay(date)
+		notable, notableName, notableFlag := cal.NotableDay(date)
+		halfDay, _ := HalfDay(cal, date)
+		weekend := WeekendDay(cal, date)
+		rows[i] = DateDimension{
+			Date:        date,
+			Year:        date.Year(),
+			Quarter:     (int(date.Month())-1)/3 + 1,
+			Month:       int(date.Month()),
+			Day:         date.Day(),
+			DayOfYear:   date.YearDay(),
+			ISOYear:     isoYear,
+			ISOWeek:     isoWeek,
+			Weekday:     date.Weekday(),
+			WeekdayName: cal.DayName(date.Weekday()),
+			MonthName:   cal.MonthName(date.Month()),
+			RedDay:      red || weekend,
+			Holiday:     red,
+			Weekend:     weekend,
+			BusinessDay: BusinessDay(cal, date),
+			NotableDay:  notable,
+			FlagDay:     redFlag || notableFlag,
+			HalfDay:     halfDay,
+		}
+		if red {
+			rows[i].HolidayName = holidayName
+		}
+		if notable {
+			rows[i].NotableName = notableName
+		}
+		rows[i].DaysToNextHoliday = -1
+		if !nextHoliday.IsZero() {
+			rows[i].DaysToNextHoliday = actualDays(date, nextHoliday)
+		}
+		if red {
+			nextHoliday = date
+		}
+	}
+
+	// Number the business days within each month, counting from the first of the month
+	count := 0
+	for date := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.UTC); date.Before(first); date = date.AddDate(0, 0, 1) {
+		if BusinessDay(cal, date) {
+			count++
+		}
+	}
+	for i := range rows {
+		if rows[i].Day == 1 {
+			count = 0
+		}
+		if rows[i].BusinessDay {
+			count++
+			rows[i].BusinessDayOfMonth = count
+		}
+	}
+	return rows
+}
+
+// WriteDateDimensionsCSV writes the rows as CSV, with a header
+func WriteDateDimensionsCSV(w io.Writer, rows []DateDimension) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(dateDimensionColumns); err != nil {
+		return err
+	}
+	record := make([]string, len(dateDimensionColumns))
+	for _, row := range rows {
+		for i, value := range row.values() {
+			record[i] = fmt.Sprint(value)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteDateDimensionsJSONLines writes the rows as JSON Lines, one JSON object per line
+func WriteDateDimensionsJSONLines(w io.Writer, rows []DateDimension) error {
+	var sb strings.Builder
+	for _, row := range rows {
+		sb.Reset()
+		sb.WriteString("{")
+		for i, value := range row.values() {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			data, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			sb.WriteString(strconv.Quote(dateDimensionColumns[i]) + ":" + string(data))
+		}
+		sb.WriteString("}\n")
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Checks if the given table name is safe to use in an SQL statement,
+// like "dim_date" or "warehouse.dim_date"
+func validTableName(table string) bool {
+	if table == "" {
+		return false
+	}
+	for _, r := range table {
+		if !(r == '_' || r == '.' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')) {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteDateDimensionsSQL writes the rows as SQL INSERT statements for the given table
+func WriteDateDimensionsSQL(w io.Writer, table string, rows []DateDimension) error {
+	if !validTableName(table) {
+		return errors.New("Invalid table name: " + table)
+	}
+	columns := strings.Join(dateDimensionColumns, ", ")
+	var sb strings.Builder
+	for _, row := range rows {
+		sb.Reset()
+		sb.WriteString("INSERT INTO " + table + " (" + columns + ") VALUES (")
+		for i, value := range row.values() {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			switch v := value.(type) {
+			case string:
+				sb.WriteString("'" + strings.ReplaceAll(v, "'", "''") + "'")
+			case bool:
+				sb.WriteString(strings.ToUpper(strconv.FormatBool(v)))
+			default:
+				sb.WriteString(fmt.Sprint(v))
+			}
+		}
+		sb.WriteString(");\n")
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package kal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDateDimensions(t *testing.T) {
+	cal := NewNorwegianCalendar()
+	rows := DateDimensions(cal, date(2025, time.April, 28), date(2025, time.May, 2))
+	if len(rows) != 5 {
+		t.Fatalf("got %d rows, want 5", len(rows))
+	}
+	// 28 April is the 17th business day of April, counting from the 1st,
+	// since påske took three business days. 1 May is a holiday.
+	if rows[0].BusinessDayOfMonth != 17 || rows[0].DaysToNextHoliday != 3 {
+		t.Errorf("28 April: got %+v", rows[0])
+	}
+	if !rows[3].Holiday || rows[3].HolidayName != "Arbeidernes internasjonale kampdag" || rows[3].BusinessDayOfMonth != 0 {
+		t.Errorf("1 May: got %+v", rows[3])
+	}
+	if rows[4].BusinessDayOfMonth != 1 || rows[4].WeekdayName != "fredag" {
+		t.Errorf("2 May: got %+v", rows[4])
+	}
+
+	// Julaften is a red half day, and a business day until 12:00
+	if row := DateDimensions(cal, date(2025, time.December, 24), date(2025, time.December, 24))[0]; !row.HalfDay || !row.Holiday || !row.BusinessDay {
+		t.Errorf("2025-12-24: got %+v", row)
+	}
+	// Påskeaften is a half day, even on a Saturday
+	if row := DateDimensions(cal, date(2025, time.April, 19), date(2025, time.April, 19))[0]; !row.HalfDay || row.Holiday || row.BusinessDay {
+		t.Errorf("2025-04-19: got %+v", row)
+	}
+
+	var sb strings.Builder
+	if err := WriteDateDimensionsSQL(&sb, "dim_date; DROP TABLE x", rows); err == nil {
+		t.Error("expected an error for an invalid table name")
+	}
+	if err := WriteDateDimensionsSQL(&sb, "dim_date", rows[3:4]); err != nil || !strings.Contains(sb.String(), "'Arbeidernes internasjonale kampdag', '', 0, 16);") {
+		t.Errorf("WriteDateDimensionsSQL: got %q, %v", sb.String(), err)
+	}
+}
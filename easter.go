@@ -53,15 +53,6 @@ func easterDayGauss(year int) (month, day int, err error) {
 	return month, day, nil
 }
 
-// Checks if a day is at Easter day +- a few days
-func atEasterPlus(date time.Time, days int) bool {
-	year := date.Year()
-	eastermonth, easterday := easterDaySpencerJones(year)
-	easter := time.Date(year, time.Month(eastermonth), easterday, 0, 0, 0, 0, time.UTC)
-	when := easter.AddDate(0, 0, days)
-	return atDate(date, when)
-}
-
 // Returns the Easter day for any given year
 func EasterDay(year int) time.Time {
 	month, day := easterDaySpencerJones(year)
@@ -21,124 +21,80 @@ func NewUSCalendar() USCalendar {
 // Finds the US name for a day of the week.
 // Note that time.Weekday starts at 0 with Sunday, not Monday.
 func (nc USCalendar) DayName(day time.Weekday) string {
-	return day.String()
+	return englishCatalog.DayName(day)
 }
 
 // Finds the US name for a given month
 func (nc USCalendar) MonthName(month time.Month) string {
-	return month.String()
+	return englishCatalog.MonthName(month)
 }
 
-// Checks if a given date is a "red day" (public holiday) in the US calendar.
-// Returns true/false, a description and true/false for if it's a flag day.
-// The dates will never overlap.
-func (nc USCalendar) RedDay(date time.Time) (bool, string, bool) {
+// The red days and notable days in the US. The names are in the catalogs.
+var usRules = []rule{
 
 	// Source: http://en.wikipedia.org/wiki/Public_holidays_in_the_United_States
 	// Source: http://timpanogos.wordpress.com/flag-fly-dates/
 
-	var (
-		desc string
-		flag bool
-	)
-
-	// Sundays
-	if date.Weekday() == time.Sunday {
-		desc = "Sunday"
-	}
-
 	// Election Day
-	if atElectionDay(date) {
-		desc = "Election Day"
-		flag = false
-	}
+	{"election_day", "", true, false, electionDay},
 
 	// New Year's Day
-	if atMD(date, 1, 1) {
-		desc = "New Year's Day"
-		flag = true
-	}
+	{"new_years_day", "", true, true, fixedDate(time.January, 1)},
 
 	// Birthday of Dr. Martin Luther King, Jr.
-	if atNthWeekdayOfMonth(date, 3, time.Monday, time.January) {
-		desc = "Martin Luther King Day"
-		flag = true
-	}
+	{"martin_luther_king_day", "", true, true, nthWeekday(3, time.Monday, time.January)},
 
 	// Inauguration Day
-	if atInaugurationDay(date) {
-		desc = "Inauguration Day"
-		flag = true
-	}
+	{"inauguration_day", "", true, true, inaugurationDay},
 
 	// Lincoln's birthday
-	if atMD(date, 2, 12) {
-		desc = "Lincoln's birthday"
-		flag = true
-	}
+	{"lincolns_birthday", "", true, true, fixedDate(time.February, 12)},
 
 	// Washington's Birthday / Presidents' Day
-	if atNthWeekdayOfMonth(date, 3, time.Monday, time.February) {
-		desc = "Presidents' Day"
-		flag = true
-	}
+	{"presidents_day", "", true, true, nthWeekday(3, time.Monday, time.February)},
 
 	// Armed Forces Day
-	if atNthWeekdayOfMonth(date, 3, time.Saturday, time.May) {
-		desc = "Armed Forces Day"
-		flag = true
-	}
+	{"armed_forces_day", "", true, true, nthWeekday(3, time.Saturday, time.May)},
 
 	// Memorial Day
-	if atLastWeekday(date, time.Monday, time.May) {
-		desc = "Memorial Day"
-		flag = true
-	}
+	{"memorial_day", "", true, true, lastWeekday(time.Monday, time.May)},
 
 	// 4th of July
-	if atMD(date, 7, 4) {
-		desc = "Independence Day"
-		flag = true
-	}
+	{"independence_day", "", true, true, fixedDate(time.July, 4)},
 
 	// Labor Day
-	if atNthWeekdayOfMonth(date, 1, time.Monday, time.September) {
-		desc = "Labor Day"
-		flag = true
-	}
+	{"labor_day", "", true, true, nthWeekday(1, time.Monday, time.September)},
 
 	// Columbus Day
-	if atNthWeekdayOfMonth(date, 2, time.Monday, time.October) {
-		desc = "Columbus Day"
-		flag = true
-	}
+	{"columbus_day", "", true, true, nthWeekday(2, time.Monday, time.October)},
 
 	// Veterans Day
-	if atMD(date, 11, 11) {
-		desc = "Veterans Day"
-		flag = true
-	}
+	{"veterans_day", "", true, true, fixedDate(time.November, 11)},
 
 	// Thanksgiving Day
-	if atNthWeekdayOfMonth(date, 4, time.Thursday, time.November) {
-		desc = "Thanksgiving Day"
-		flag = true
-	}
+	{"thanksgiving_day", "", true, true, nthWeekday(4, time.Thursday, time.November)},
 
 	// Christmas
-	if atMD(date, 12, 25) {
-		desc = "Christmas Day"
-		flag = true
-	}
+	{"christmas_day", "", true, true, fixedDate(time.December, 25)},
 
-	// Red days
-	if desc != "" {
-		// Then return
-		return true, desc, flag
-	}
+	// --- Notable days ---
 
-	// Normal days
-	return false, desc, false
+	// Since days may overlap, flag flying days must come first.
+
+	// --- Flag flying days ---
+
+	// --- Other days ---
+}
+
+// The red days and notable days in the US calendar, with the English names
+var usDays = localRules(regions["US"], englishCatalog)
+
+// Checks if a given date is a "red day" (public holiday) in the US calendar.
+// Returns true/false, a description and true/false for if it's a flag day.
+// Weekend days are not red days, unless they are also public holidays.
+// The dates will never overlap.
+func (nc USCalendar) RedDay(date time.Time) (bool, string, bool) {
+	return usDays.redDay(date)
 }
 
 // Some days are not red, but special in one way or another.
@@ -147,20 +103,27 @@ func (nc USCalendar) RedDay(date time.Time) (bool, string, bool) {
 // than one notable event that day) and true/false depending on if it's a flag
 // flying day or not.
 func (nc USCalendar) NotableDay(date time.Time) (bool, string, bool) {
+	return usDays.notableDay(date)
+}
 
-	var (
-	//descriptions []string
-	//flag         bool
-	)
-
-	// Since days may overlap, flag flying days must come first.
-
-	// --- Flag flying days ---
+// Checks if a given date is a partial day off in the US calendar, where only
+// the part of the day after a given time of day is off. Returns true/false
+// and the time of day when the time off starts, like 12 hours for 12:00.
+func (nc USCalendar) HalfDay(date time.Time) (bool, time.Duration) {
+	return usDays.halfDay(date)
+}
 
-	// --- Other days ---
+// Describe what type of day a given date is
+func (nc USCalendar) describe(date time.Time, weekend bool) string {
+	if desc, ok := usDays.describe(date, weekend); ok {
+		return desc
+	}
+	return nc.NormalDay()
+}
 
-	// No notable events
-	return false, "", false
+// List the red days and notable days of the given year
+func (nc USCalendar) specialDays(year int) []SpecialDay {
+	return usDays.specialDays(year, true, true)
 }
 
 // Checks if a given date is in a notable time range (summer holidays, for instance)
@@ -172,11 +135,26 @@ func (nc USCalendar) NotablePeriod(date time.Time) (bool, string) {
 	return false, ""
 }
 
+// Saturday and Sunday
+func (nc USCalendar) Weekend() []time.Weekday {
+	return saturdaySunday()
+}
+
 func (nc USCalendar) MondayFirst() bool {
 	return false
 }
 
 // An ordinary day
 func (nc USCalendar) NormalDay() string {
-	return "Ordinary"
+	return englishCatalog.NormalDay
+}
+
+// The English words for the parts of a period
+func (nc USCalendar) periodWords() periodWords {
+	return englishCatalog.periodWords()
+}
+
+// The English names
+func (nc USCalendar) catalog() *Catalog {
+	return englishCatalog
 }
@@ -0,0 +1,198 @@
+package kal
+
+// Calendars with custom recurring events, like birthdays or board meetings
+
+import (
+	"strings"
+	"time"
+)
+
+// An Event is a named recurring event. An event with a Duration is on
+// every date that an occurrence touches, like a week off from Monday to
+// Friday, or a night shift from 22:00 to 06:00.
+type Event struct {
+	ID         string // an identifier, like the UID of an iCalendar event
+	Name       string
+	Red        bool // true if the event is a day off, like a public holiday
+	Flag       bool // true if it is a flag flying day
+	Recurrence Recurrence
+	Duration   time.Duration  // the length of each occurrence, 0 for a single point in time
+	Location   *time.Location // the time zone of the dates, the time zone of the start if nil
+}
+
+// The time zone of the dates that the event is on
+func (e Event) location() *time.Location {
+	if e.Location != nil {
+		return e.Location
+	}
+	return e.Recurrence.Dtstart.Location()
+}
+
+// Find the occurrences of the event that touch the days from and including
+// the given start of a day, to and excluding the given end of a day
+func (e Event) touching(start, end time.Time) []time.Time {
+	if e.Duration <= 0 {
+		return e.Recurrence.Between(start, end)
+	}
+	// An occurrence touches the days if it ends after the first one starts
+	return e.Recurrence.Between(start.Add(-e.Duration+time.Nanosecond), end)
+}
+
+// Checks if the event is on the date of the given time
+func (e Event) onDate(date time.Time) bool {
+	year, month, day := date.Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, e.location())
+	return len(e.touching(start, start.AddDate(0, 0, 1))) > 0
+}
+
+// An EventCalendar is a Calendar with custom events in addition to the days
+// of the wrapped calendar. Events that are red days are found by RedDay,
+// the other events are found by NotableDay. Describe includes the events.
+type EventCalendar struct {
+	Calendar
+	Events []Event
+}
+
+// Create a new EventCalendar with the given events, on top of the given
+// calendar. If cal is nil, a calendar with English names and no other
+// special days is used.
+func NewEventCalendar(cal Calendar, events ...Event) EventCalendar {
+	if cal == nil {
+		cal = plainCalendar{}
+	}
+	return EventCalendar{cal, events}
+}
+
+// Find the events on the given date that are red days, or that are not
+func (ec EventCalendar) events(date time.Time, red bool) (bool, string, bool) {
+	var names []string
+	flag := false
+	for _, event := range ec.Events {
+		if event.Red == red && event.onDate(date) {
+			names = append(names, event.Name)
+			flag = flag || event.Flag
+		}
+	}
+	return len(names) > 0, strings.Join(names, ", "), flag
+}
+
+// Checks if a given date is a red day in the wrapped calendar, or a day
+// with an event that is a red day. Returns true/false, a description and
+// true/false for if it's a flag day.
+func (ec EventCalendar) RedDay(date time.Time) (bool, string, bool) {
+	if red, desc, flag := ec.Calendar.RedDay(date); red {
+		return red, desc, flag
+	}
+	return ec.events(date, true)
+}
+
+// Checks if a given date is a notable day in the wrapped calendar, or has
+// events that are not red days. Returns true/false, the names and
+// true/false for if it's a flag day.
+func (ec EventCalendar) NotableDay(date time.Time) (bool, string, bool) {
+	notable, desc, flag := ec.Calendar.NotableDay(date)
+	if eventNotable, eventDesc, eventFlag := ec.events(date, false); eventNotable {
+		if notable {
+			desc += ", " + eventDesc
+		} else {
+			desc = eventDesc
+		}
+		return true, desc, flag || eventFlag
+	}
+	return notable, desc, flag
+}
+
+// Checks if a given date is a partial day off in the wrapped calendar
+func (ec EventCalendar) HalfDay(date time.Time) (bool, time.Duration) {
+	return HalfDay(ec.Calendar, date)
+}
+
+// The days of the weekend in the wrapped calendar
+func (ec EventCalendar) Weekend() []time.Weekday {
+	return Weekend(ec.Calendar)
+}
+
+// Find the words for formatting a period with the wrapped calendar
+func (ec EventCalendar) periodWords() periodWords {
+	return calendarPeriodWords(ec.Calendar)
+}
+
+// The catalog with the names of the wrapped calendar
+func (ec EventCalendar) catalog() *Catalog {
+	return CalendarCatalog(ec.Calendar)
+}
+
+// List the special days of the wrapped calendar together with the events of
+// the given year. Events without an ID are identified by their name.
+func (ec EventCalendar) specialDays(year int) []SpecialDay {
+	days := calendarSpecialDays(ec.Calendar, year)
+	first := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	for _, event := range ec.Events {
+		id := event.ID
+		if id == "" {
+			id = specialDayID(event.Name)
+		}
+		loc := event.location()
+		seen := make(map[time.Time]bool)
+		for _, t := range event.touching(time.Date(year, time.January, 1, 0, 0, 0, 0, loc), time.Date(year+1, time.January, 1, 0, 0, 0, 0, loc)) {
+			// The first and the last date that the occurrence touches
+			end := t
+			if event.Duration > 0 {
+				end = t.Add(event.Duration - time.Nanosecond)
+			}
+			y, m, d := t.In(loc).Date()
+			start := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+			y, m, d = end.In(loc).Date()
+			end = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+			for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+				if seen[date] || date.Before(first) || date.After(last) {
+					continue
+				}
+				seen[date] = true
+				days = append(days, SpecialDay{ID: id, Name: event.Name, Date: date, Red: event.Red, Flag: event.Flag})
+			}
+		}
+	}
+	sortSpecialDays(days)
+	return days
+}
+
+// plainCalendar is a calendar with English names and no special days
+type plainCalendar struct{}
+
+func (pc plainCalendar) DayName(day time.Weekday) string {
+	return day.String()
+}
+
+func (pc plainCalendar) MonthName(month time.Month) string {
+	return month.String()
+}
+
+func (pc plainCalendar) RedDay(date time.Time) (bool, string, bool) {
+	return false, "", false
+}
+
+func (pc plainCalendar) NotableDay(date time.Time) (bool, string, bool) {
+	return false, "", false
+}
+
+func (pc plainCalendar) HalfDay(date time.Time) (bool, time.Duration) {
+	return false, 0
+}
+
+func (pc plainCalendar) NotablePeriod(date time.Time) (bool, string) {
+	return false, ""
+}
+
+func (pc plainCalendar) MondayFirst() bool {
+	return true
+}
+
+func (pc plainCalendar) Weekend() []time.Weekday {
+	return saturdaySunday()
+}
+
+func (pc plainCalendar) NormalDay() string {
+	return "Ordinary"
+}
@@ -0,0 +1,254 @@
+package kal
+
+// Trading calendars for stock exchanges, with trading sessions and early closes
+
+import (
+	"time"
+)
+
+// A Session is a trading session on a stock exchange, in the time zone of the exchange
+type Session struct {
+	Open       time.Time
+	Close      time.Time
+	EarlyClose bool // true if the exchange closes earlier than usual
+}
+
+// An Exchange is a Calendar for a stock exchange, that also knows the
+// trading sessions. The HalfDay method reports the early closes.
+type Exchange interface {
+	Calendar
+	HalfDay(time.Time) (bool, time.Duration)
+	Location() *time.Location
+	Session(date time.Time) (Session, bool)
+}
+
+// Load the given time zone, or use a fixed time zone with the given offset
+// in hours if the time zone database is not available
+func loadLocation(name string, offset int) *time.Location {
+	if loc, err := time.LoadLocation(name); err == nil {
+		return loc
+	}
+	return time.FixedZone(name, offset*60*60)
+}
+
+// Find the trading session on the given date, for an exchange that is open
+// between the given times of day, in the given time zone
+func session(ex Exchange, date time.Time, open, close time.Duration) (Session, bool) {
+	year, month, day := date.Date()
+	date = time.Date(year, month, day, 0, 0, 0, 0, ex.Location())
+	if !BusinessDay(ex, date) {
+		return Session{}, false
+	}
+	early, start := ex.HalfDay(date)
+	if early && start < close {
+		close = start
+	}
+	return Session{
+		Open:       atTimeOfDay(year, month, day, open, ex.Location()),
+		Close:      atTimeOfDay(year, month, day, close, ex.Location()),
+		EarlyClose: early,
+	}, true
+}
+
+// The dates of from and to, at midnight in the given location
+func dateSpan(from, to time.Time, loc *time.Location) (time.Time, time.Time) {
+	year, month, day := from.Date()
+	first := time.Date(year, month, day, 0, 0, 0, 0, loc)
+	year, month, day = to.Date()
+	return first, time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+// TradingSessions returns the trading sessions of an exchange, from and
+// including the date of from, to and including the date of to
+func TradingSessions(ex Exchange, from, to time.Time) []Session {
+	var sessions []Session
+	current, last := dateSpan(from, to, time.UTC)
+	for !current.After(last) {
+		if s, ok := ex.Session(current); ok {
+			sessions = append(sessions, s)
+		}
+		current = current.AddDate(0, 0, 1)
+	}
+	return sessions
+}
+
+// The nearest weekday for the dates that fall on a weekend, that is,
+// Friday for a Saturday and Monday for a Sunday
+func observedOnWeekday(fn dateFunc) dateFunc {
+	return func(year int) []time.Time {
+		var dates []time.Time
+		for _, date := range fn(year) {
+			switch date.Weekday() {
+			case time.Saturday:
+				dates = append(dates, date.AddDate(0, 0, -1))
+			case time.Sunday:
+				dates = append(dates, date.AddDate(0, 0, 1))
+			}
+		}
+		return dates
+	}
+}
+
+// A date from the given function, if it falls on one of the given weekdays
+func onWeekdays(fn dateFunc, weekdays ...time.Weekday) dateFunc {
+	return func(year int) []time.Time {
+		var dates []time.Time
+		for _, date := range fn(year) {
+			for _, weekday := range weekdays {
+				if date.Weekday() == weekday {
+					dates = append(dates, date)
+				}
+			}
+		}
+		return dates
+	}
+}
+
+// --- NYSE ---
+
+// NYSECalendar is the trading calendar for the New York Stock Exchange
+type NYSECalendar struct {
+	USCalendar
+}
+
+// Create a new NYSE calendar
+func NewNYSECalendar() NYSECalendar {
+	return NYSECalendar{}
+}
+
+// The time zone of the New York Stock Exchange
+var nyseLocation = loadLocation("America/New_York", -5)
+
+// The days when the NYSE is closed, in addition to weekends, and the early closes.
+// Holidays on a Saturday are observed the Friday before, except for New Year's Day,
+// and holidays on a Sunday are observed the Monday after.
+var nyseDays = newRuleSet(USCalendar{}.DayName, []rule{
+
+	// Source: https://www.nyse.com/markets/hours-calendars
+
+	{"new_years_day", "New Year's Day", true, false, fixedDate(time.January, 1)},
+	{"new_years_day_observed", "New Year's Day (observed)", true, false, observedOnMonday(fixedDate(time.January, 1))},
+	{"martin_luther_king_day", "Martin Luther King, Jr. Day", true, false, nthWeekday(3, time.Monday, time.January)},
+	{"presidents_day", "Washington's Birthday", true, false, nthWeekday(3, time.Monday, time.February)},
+	{"good_friday", "Good Friday", true, false, easterPlus(-2)},
+	{"memorial_day", "Memorial Day", true, false, lastWeekday(time.Monday, time.May)},
+	{"juneteenth", "Juneteenth National Independence Day", true, false, juneteenth},
+	{"juneteenth_observed", "Juneteenth National Independence Day (observed)", true, false, observedOnWeekday(juneteenth)},
+	{"independence_day", "Independence Day", true, false, fixedDate(time.July, 4)},
+	{"independence_day_observed", "Independence Day (observed)", true, false, observedOnWeekday(fixedDate(time.July, 4))},
+	{"labor_day", "Labor Day", true, false, nthWeekday(1, time.Monday, time.September)},
+	{"thanksgiving_day", "Thanksgiving Day", true, false, nthWeekday(4, time.Thursday, time.November)},
+	{"christmas_day", "Christmas Day", true, false, fixedDate(time.December, 25)},
+	{"christmas_day_observed", "Christmas Day (observed)", true, false, observedOnWeekday(fixedDate(time.December, 25))},
+
+	// --- Early closes, at 13:00 ---
+
+	{"independence_day_eve", "Day before Independence Day", false, false, onWeekdays(fixedDate(time.July, 3), time.Monday, time.Tuesday, time.Wednesday, time.Thursday)},
+	{"black_friday", "Day after Thanksgiving", false, false, dayAfterThanksgiving},
+	{"christmas_eve", "Christmas Eve", false, false, onWeekdays(fixedDate(time.December, 24), time.Monday, time.Tuesday, time.Wednesday, time.Thursday)},
+}).withHalfDays(map[string]time.Duration{
+	"independence_day_eve": 13 * time.Hour,
+	"black_friday":         13 * time.Hour,
+	"christmas_eve":        13 * time.Hour,
+})
+
+// The Friday after Thanksgiving Day
+func dayAfterThanksgiving(year int) []time.Time {
+	var dates []time.Time
+	for _, date := range nthWeekday(4, time.Thursday, time.November)(year) {
+		dates = append(dates, date.AddDate(0, 0, 1))
+	}
+	return dates
+}
+
+// The time zone of the exchange
+func (nc NYSECalendar) Location() *time.Location {
+	return nyseLocation
+}
+
+// Checks if a given date is a day when the NYSE is closed, other than
+// a weekend day. Returns true/false, a description and false, since flag
+// days are found in the US calendar instead.
+func (nc NYSECalendar) RedDay(date time.Time) (bool, string, bool) {
+	return nyseDays.redDay(date)
+}
+
+// Checks if a given date is an early close
+func (nc NYSECalendar) NotableDay(date time.Time) (bool, string, bool) {
+	return nyseDays.notableDay(date)
+}
+
+// Checks if a given date is an early close, returning true/false and the
+// time of day when the exchange closes
+func (nc NYSECalendar) HalfDay(date time.Time) (bool, time.Duration) {
+	return nyseDays.halfDay(date)
+}
+
+// Finds the trading session for a given date, from 09:30 to 16:00, or to
+// 13:00 on early closes. Returns false if the exchange is closed.
+func (nc NYSECalendar) Session(date time.Time) (Session, bool) {
+	return session(nc, date, 9*time.Hour+30*time.Minute, 16*time.Hour)
+}
+
+// An ordinary day
+func (nc NYSECalendar) NormalDay() string {
+	return "Trading day"
+}
+
+// Describe what type of day a given date is
+func (nc NYSECalendar) describe(date time.Time, weekend bool) string {
+	if desc, ok := nyseDays.describe(date, weekend); ok {
+		return desc
+	}
+	return nc.NormalDay()
+}
+
+// List the closing days and early closes of the given year
+func (nc NYSECalendar) specialDays(year int) []SpecialDay {
+	return nyseDays.specialDays(year, true, true)
+}
+
+// --- Oslo Børs ---
+
+// OsloBorsCalendar is the trading calendar for Oslo Børs. It is closed on
+// the same days as Norwegian banks, including julaften and nyttårsaften.
+// There are no scheduled early closes, since julaften and nyttårsaften,
+// which are half days in the Norwegian calendar, are closed all day.
+type OsloBorsCalendar struct {
+	NorwegianBankCalendar
+}
+
+// Create a new Oslo Børs calendar
+func NewOsloBorsCalendar() OsloBorsCalendar {
+	return OsloBorsCalendar{}
+}
+
+// The time zone of Oslo Børs
+var osloLocation = loadLocation("Europe/Oslo", 1)
+
+// The time zone of the exchange
+func (oc OsloBorsCalendar) Location() *time.Location {
+	return osloLocation
+}
+
+// Finds the trading session for a given date, from 09:00 to 16:20.
+// Returns false if the exchange is closed.
+// The sessions are never closed early, see HalfDay.
+func (oc OsloBorsCalendar) Session(date time.Time) (Session, bool) {
+	return session(oc, date, 9*time.Hour, 16*time.Hour+20*time.Minute)
+}
+
+// There are no early closes on Oslo Børs
+func (oc OsloBorsCalendar) HalfDay(date time.Time) (bool, time.Duration) {
+	return false, 0
+}
+
+// An ordinary day
+func (oc OsloBorsCalendar) NormalDay() string {
+	return "Handelsdag"
+}
+
+// Describe what type of day a given date is
+func (oc OsloBorsCalendar) describe(date time.Time, weekend bool) string {
+	return describe(oc, date, weekend)
+}
@@ -0,0 +1,60 @@
+package kal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExchangeCalendars(t *testing.T) {
+	nyse, oslo := NewNYSECalendar(), NewOsloBorsCalendar()
+	tests := []struct {
+		ex    Exchange
+		date  time.Time
+		open  bool
+		close time.Duration // the closing time, for open days
+	}{
+		{nyse, date(2025, time.January, 1), false, 0},
+		{nyse, date(2025, time.April, 18), false, 0},                // Good Friday
+		{nyse, date(2025, time.October, 13), true, 16 * time.Hour},  // Columbus Day
+		{nyse, date(2025, time.November, 11), true, 16 * time.Hour}, // Veterans Day
+		{nyse, date(2025, time.July, 3), true, 13 * time.Hour},
+		{nyse, date(2025, time.July, 4), false, 0},
+		{nyse, date(2025, time.November, 27), false, 0},
+		{nyse, date(2025, time.November, 28), true, 13 * time.Hour},
+		{nyse, date(2025, time.December, 24), true, 13 * time.Hour},
+		{nyse, date(2021, time.December, 31), true, 16 * time.Hour}, // New Year's Day on a Saturday is not observed
+		{nyse, date(2022, time.December, 26), false, 0},             // Christmas Day on a Sunday
+		{nyse, date(2027, time.June, 18), false, 0},                 // Juneteenth on a Saturday
+		{nyse, date(2020, time.July, 3), false, 0},                  // Independence Day on a Saturday
+
+		{oslo, date(2025, time.April, 16), true, 16*time.Hour + 20*time.Minute},
+		{oslo, date(2025, time.April, 17), false, 0}, // Skjærtorsdag
+		{oslo, date(2025, time.May, 29), false, 0},   // Kristi himmelfartsdag
+		{oslo, date(2025, time.December, 23), true, 16*time.Hour + 20*time.Minute},
+		{oslo, date(2025, time.December, 24), false, 0},
+		{oslo, date(2025, time.December, 31), false, 0},
+	}
+	for _, test := range tests {
+		s, ok := test.ex.Session(test.date)
+		if ok != test.open {
+			t.Errorf("%s: got open %v, want %v", test.date.Format("2006-01-02"), ok, test.open)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		year, month, day := test.date.Date()
+		if want := atTimeOfDay(year, month, day, test.close, test.ex.Location()); !s.Close.Equal(want) || s.EarlyClose != (test.close < 16*time.Hour) {
+			t.Errorf("%s: got close %s, early %v, want %s", test.date.Format("2006-01-02"), s.Close, s.EarlyClose, want)
+		}
+		if s.Open.Location() != test.ex.Location() {
+			t.Errorf("%s: got the session in %s, want %s", test.date.Format("2006-01-02"), s.Open.Location(), test.ex.Location())
+		}
+	}
+
+	// 22, 23, 24 (early close) and 26 December
+	sessions := TradingSessions(nyse, date(2025, time.December, 22), date(2025, time.December, 28))
+	if len(sessions) != 4 || !sessions[2].EarlyClose || sessions[3].Open.Day() != 26 {
+		t.Errorf("got %+v", sessions)
+	}
+}
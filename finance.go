@@ -0,0 +1,340 @@
+package kal
+
+// Financial date adjustment conventions, day count fractions and coupon schedules
+
+import (
+	"errors"
+	"time"
+)
+
+// A Convention is a business day convention, for adjusting a date that
+// is not a business day
+type Convention int
+
+const (
+	// Unadjusted leaves the date as it is
+	Unadjusted Convention = iota
+	// Following moves the date to the next business day
+	Following
+	// ModifiedFollowing moves the date to the next business day, unless
+	// that is in the next month, then to the previous business day
+	ModifiedFollowing
+	// Preceding moves the date to the previous business day
+	Preceding
+	// ModifiedPreceding moves the date to the previous business day, unless
+	// that is in the previous month, then to the next business day
+	ModifiedPreceding
+)
+
+// String returns the name of the business day convention
+func (c Convention) String() string {
+	switch c {
+	case Unadjusted:
+		return "Unadjusted"
+	case Following:
+		return "Following"
+	case ModifiedFollowing:
+		return "Modified Following"
+	case Preceding:
+		return "Preceding"
+	case ModifiedPreceding:
+		return "Modified Preceding"
+	}
+	return "Unknown"
+}
+
+// Find the first business day in the given direction, starting with the
+// given date. Returns false if there is none within maxSearchDays days.
+func searchBusinessDay(cal Calendar, date time.Time, step int) (time.Time, bool) {
+	for i := 0; i < maxSearchDays; i++ {
+		if BusinessDay(cal, date) {
+			return date, true
+		}
+		date = date.AddDate(0, 0, step)
+	}
+	return time.Time{}, false
+}
+
+// Adjust moves the given date to a business day in the given calendar,
+// according to the given business day convention. If there are no business
+// days within about ten years in the directions that the convention allows,
+// like for a calendar where every day is a holiday, the date is returned
+// unadjusted. Use BusinessDay to check the result if that can happen.
+func Adjust(cal Calendar, date time.Time, c Convention) time.Time {
+	switch c {
+	case Following:
+		if adjusted, ok := searchBusinessDay(cal, date, 1); ok {
+			return adjusted
+		}
+	case ModifiedFollowing:
+		if adjusted, ok := searchBusinessDay(cal, date, 1); ok && adjusted.Month() == date.Month() {
+			return adjusted
+		}
+		if adjusted, ok := searchBusinessDay(cal, date, -1); ok {
+			return adjusted
+		}
+	case Preceding:
+		if adjusted, ok := searchBusinessDay(cal, date, -1); ok {
+			return adjusted
+		}
+	case ModifiedPreceding:
+		if adjusted, ok := searchBusinessDay(cal, date, -1); ok && adjusted.Month() == date.Month() {
+			return adjusted
+		}
+		if adjusted, ok := searchBusinessDay(cal, date, 1); ok {
+			return adjusted
+		}
+	}
+	return date
+}
+
+// The number of days in the month of the given date
+func daysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// Checks if the given date is the last day of its month
+func lastDayInMonth(date time.Time) bool {
+	return date.Day() == daysInMonth(date.Year(), date.Month())
+}
+
+// AddMonths adds the given number of months to a date. If the day does not
+// exist in the resulting month, the last day of that month is used instead,
+// so that January 31st + 1 month is February 28th or 29th.
+// If endOfMonth is true and the given date is the last day of its month,
+// the result is also the last day of its month, so that February 28th
+// + 1 month is March 31st.
+func AddMonths(date time.Time, months int, endOfMonth bool) time.Time {
+	year, month, day := date.Date()
+	hour, min, sec := date.Clock()
+	first := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	last := daysInMonth(first.Year(), first.Month())
+	if day > last || (endOfMonth && lastDayInMonth(date)) {
+		day = last
+	}
+	return time.Date(first.Year(), first.Month(), day, hour, min, sec, date.Nanosecond(), date.Location())
+}
+
+// A DayCount is a day count convention, for finding the fraction of a year
+// between two dates
+type DayCount int
+
+const (
+	// ACT360 is the actual number of days, divided by 360
+	ACT360 DayCount = iota
+	// ACT365F is the actual number of days, divided by 365
+	ACT365F
+	// Thirty360US is 30/360 US (bond basis), with the end of February rules
+	Thirty360US
+	// Thirty360E is 30E/360 (Eurobond basis)
+	Thirty360E
+	// ACTACTISDA is the actual number of days in leap years divided by 366,
+	// plus the actual number of days in other years divided by 365
+	ACTACTISDA
+)
+
+// String returns the name of the day count convention
+func (dc DayCount) String() string {
+	switch dc {
+	case ACT360:
+		return "ACT/360"
+	case ACT365F:
+		return "ACT/365F"
+	case Thirty360US:
+		return "30/360 US"
+	case Thirty360E:
+		return "30E/360"
+	case ACTACTISDA:
+		return "ACT/ACT ISDA"
+	}
+	return "Unknown"
+}
+
+// The actual number of days between two dates, ignoring the time of day
+func actualDays(start, end time.Time) int {
+	y1, m1, d1 := start.Date()
+	y2, m2, d2 := end.Date()
+	a := time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)
+	b := time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// Checks if the given year is a leap year
+func leapYear(year int) bool {
+	return daysInMonth(year, time.February) == 29
+}
+
+// Days returns the number of days between two dates, according to the
+// day count convention
+func (dc DayCount) Days(start, end time.Time) int {
+	switch dc {
+	case Thirty360US, Thirty360E:
+		y1, m1, d1 := start.Date()
+		y2, m2, d2 := end.Date()
+		if dc == Thirty360US {
+			lastFeb1 := m1 == time.February && lastDayInMonth(start)
+			lastFeb2 := m2 == time.February && lastDayInMonth(end)
+			if lastFeb1 && lastFeb2 {
+				d2 = 30
+			}
+			if lastFeb1 {
+				d1 = 30
+			}
+			if d2 == 31 && d1 >= 30 {
+				d2 = 30
+			}
+			if d1 == 31 {
+				d1 = 30
+			}
+		} else {
+			if d1 == 31 {
+				d1 = 30
+			}
+			if d2 == 31 {
+				d2 = 30
+			}
+		}
+		return 360*(y2-y1) + 30*(int(m2)-int(m1)) + (d2 - d1)
+	}
+	return actualDays(start, end)
+}
+
+// YearFraction returns the fraction of a year between two dates,
+// according to the day count convention
+func (dc DayCount) YearFraction(start, end time.Time) float64 {
+	switch dc {
+	case ACT360, Thirty360US, Thirty360E:
+		return float64(dc.Days(start, end)) / 360.0
+	case ACT365F:
+		return float64(dc.Days(start, end)) / 365.0
+	case ACTACTISDA:
+		if end.Before(start) {
+			return -dc.YearFraction(end, start)
+		}
+		var fraction float64
+		for year := start.Year(); year <= end.Year(); year++ {
+			from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+			to := time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+			if year == start.Year() {
+				from = start
+			}
+			if year == end.Year() {
+				to = end
+			}
+			daysInYear := 365.0
+			if leapYear(year) {
+				daysInYear = 366.0
+			}
+			fraction += float64(actualDays(from, to)) / daysInYear
+		}
+		return fraction
+	}
+	return 0
+}
+
+// A Stub says where an irregular coupon period is placed in a schedule,
+// when the schedule does not divide evenly into regular periods
+type Stub int
+
+const (
+	// ShortFinal places a short period at the end
+	ShortFinal Stub = iota
+	// LongFinal merges the remainder into the last regular period
+	LongFinal
+	// ShortInitial places a short period at the start
+	ShortInitial
+	// LongInitial merges the remainder into the first regular period
+	LongInitial
+)
+
+// A CouponPeriod is a single period in a coupon schedule
+type CouponPeriod struct {
+	Start           time.Time // the adjusted start date
+	End             time.Time // the adjusted end date
+	UnadjustedStart time.Time
+	UnadjustedEnd   time.Time
+	Stub            bool // true if this is an irregular period
+}
+
+// ScheduleOptions are the options for generating a coupon schedule
+type ScheduleOptions struct {
+	Months     int        // the length of a regular period, like 6 for semi-annual coupons
+	Stub       Stub       // where to place an irregular period
+	Convention Convention // how to adjust dates that are not business days
+	EndOfMonth bool       // roll on the last day of the month, see AddMonths
+}
+
+// CouponSchedule generates the coupon periods between start and end, rolling
+// forwards from start for final stubs, or backwards from end for initial
+// stubs. The dates are adjusted to business days in the given calendar,
+// and an error is returned if there is no business day to adjust to.
+func CouponSchedule(cal Calendar, start, end time.Time, opts ScheduleOptions) ([]CouponPeriod, error) {
+	if opts.Months <= 0 {
+		return nil, errors.New("the period length must be at least one month")
+	}
+	if !end.After(start) {
+		return nil, errors.New("the end date must be after the start date")
+	}
+
+	// Find the unadjusted dates, in order, including start and end.
+	// If the last rolled date does not hit the other end, there is a stub.
+	var (
+		dates []time.Time
+		stub  bool
+	)
+	backwards := opts.Stub == ShortInitial || opts.Stub == LongInitial
+	for i := 0; ; i++ {
+		if backwards {
+			date := AddMonths(end, -i*opts.Months, opts.EndOfMonth)
+			if !date.After(start) {
+				stub = !date.Equal(start)
+				dates = append([]time.Time{start}, dates...)
+				break
+			}
+			dates = append([]time.Time{date}, dates...)
+		} else {
+			date := AddMonths(start, i*opts.Months, opts.EndOfMonth)
+			if !date.Before(end) {
+				stub = !date.Equal(end)
+				dates = append(dates, end)
+				break
+			}
+			dates = append(dates, date)
+		}
+	}
+
+	// For long stubs, merge the stub with the neighbouring regular period
+	if stub && len(dates) > 2 {
+		if opts.Stub == LongInitial {
+			dates = append(dates[:1], dates[2:]...)
+		} else if opts.Stub == LongFinal {
+			dates = append(dates[:len(dates)-2], dates[len(dates)-1])
+		}
+	}
+
+	adjusted := make([]time.Time, len(dates))
+	for i, date := range dates {
+		adjusted[i] = Adjust(cal, date, opts.Convention)
+		if opts.Convention != Unadjusted && !BusinessDay(cal, adjusted[i]) {
+			return nil, errors.New("found no business day near " + date.Format("2006-01-02"))
+		}
+	}
+
+	periods := make([]CouponPeriod, 0, len(dates)-1)
+	for i := 1; i < len(dates); i++ {
+		periods = append(periods, CouponPeriod{
+			Start:           adjusted[i-1],
+			End:             adjusted[i],
+			UnadjustedStart: dates[i-1],
+			UnadjustedEnd:   dates[i],
+		})
+	}
+	if stub {
+		if backwards {
+			periods[0].Stub = true
+		} else {
+			periods[len(periods)-1].Stub = true
+		}
+	}
+	return periods, nil
+}
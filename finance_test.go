@@ -0,0 +1,87 @@
+package kal
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestAdjust(t *testing.T) {
+	cal := NewNorwegianCalendar()
+	// Sunday 31 August 2025
+	sunday := date(2025, time.August, 31)
+	for c, want := range map[Convention]time.Time{
+		Unadjusted:        sunday,
+		Following:         date(2025, time.September, 1),
+		ModifiedFollowing: date(2025, time.August, 29),
+		Preceding:         date(2025, time.August, 29),
+		ModifiedPreceding: date(2025, time.August, 29),
+	} {
+		if got := Adjust(cal, sunday, c); !got.Equal(want) {
+			t.Errorf("%s: got %s, want %s", c, got.Format("2006-01-02"), want.Format("2006-01-02"))
+		}
+	}
+	// Langfredag and skjærtorsdag 2025 are the 18th and 17th of April
+	if got, want := Adjust(cal, date(2025, time.April, 18), Preceding), date(2025, time.April, 16); !got.Equal(want) {
+		t.Errorf("Preceding over Easter: got %s, want %s", got, want)
+	}
+}
+
+func TestAdjustWithoutBusinessDays(t *testing.T) {
+	// Every day is a weekend day, so there are no business days to adjust to
+	cal := WithWeekend(NewNorwegianCalendar(), time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday)
+	day := date(2025, time.August, 31)
+	for _, c := range []Convention{Following, ModifiedFollowing, Preceding, ModifiedPreceding} {
+		if got := Adjust(cal, day, c); !got.Equal(day) {
+			t.Errorf("%s: got %s, want the date unadjusted", c, got.Format("2006-01-02"))
+		}
+	}
+	if _, err := CouponSchedule(cal, day, date(2026, time.August, 31), ScheduleOptions{Months: 6, Convention: Following}); err == nil {
+		t.Error("expected an error for a schedule without business days")
+	}
+}
+
+func TestYearFraction(t *testing.T) {
+	start, end := date(2007, time.February, 28), date(2008, time.August, 31)
+	for dc, want := range map[DayCount]float64{
+		ACT360:      550.0 / 360.0,
+		ACT365F:     550.0 / 365.0,
+		Thirty360US: 540.0 / 360.0,
+		Thirty360E:  542.0 / 360.0,
+		ACTACTISDA:  307.0/365.0 + 243.0/366.0,
+	} {
+		if got := dc.YearFraction(start, end); math.Abs(got-want) > 1e-12 {
+			t.Errorf("%s: got %f, want %f", dc, got, want)
+		}
+	}
+}
+
+func TestCouponSchedule(t *testing.T) {
+	cal := NewNorwegianCalendar()
+	start, end := date(2025, time.January, 31), date(2026, time.May, 15)
+	periods, err := CouponSchedule(cal, start, end, ScheduleOptions{Months: 6, Stub: ShortInitial, Convention: ModifiedFollowing})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Time{start, date(2025, time.May, 15), date(2025, time.November, 15), end}
+	if len(periods) != len(want)-1 || !periods[0].Stub || periods[1].Stub {
+		t.Fatalf("unexpected periods: %+v", periods)
+	}
+	for i, p := range periods {
+		if !p.UnadjustedStart.Equal(want[i]) || !p.UnadjustedEnd.Equal(want[i+1]) {
+			t.Errorf("period %d: got %s - %s", i, p.UnadjustedStart, p.UnadjustedEnd)
+		}
+	}
+	// Saturday 15 November 2025 is adjusted to Monday 17 November
+	if !periods[1].End.Equal(date(2025, time.November, 17)) {
+		t.Errorf("expected an adjusted end date, got %s", periods[1].End)
+	}
+	periods, _ = CouponSchedule(cal, start, end, ScheduleOptions{Months: 6, Stub: LongFinal, EndOfMonth: true})
+	if len(periods) != 2 || !periods[1].UnadjustedStart.Equal(date(2025, time.July, 31)) || !periods[1].Stub {
+		t.Errorf("unexpected long final stub: %+v", periods)
+	}
+}
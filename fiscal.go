@@ -0,0 +1,165 @@
+package kal
+
+// Retail fiscal calendars, with 4-4-5, 4-5-4 or 5-4-4 week patterns and 52/53-week years
+
+import (
+	"errors"
+	"time"
+)
+
+// A FiscalPattern is the number of weeks in each of the three periods of a quarter
+type FiscalPattern [3]int
+
+// The common patterns for fiscal calendars
+var (
+	Pattern445 = FiscalPattern{4, 4, 5}
+	Pattern454 = FiscalPattern{4, 5, 4}
+	Pattern544 = FiscalPattern{5, 4, 4}
+)
+
+// A YearEndRule decides how the last day of a fiscal year is found
+type YearEndRule int
+
+const (
+	// LastWeekdayOfMonth ends the year on the last given weekday of the given month
+	LastWeekdayOfMonth YearEndRule = iota
+	// NearestWeekdayToDate ends the year on the given weekday that is nearest
+	// to the given month and day
+	NearestWeekdayToDate
+)
+
+// FiscalCalendar is a 52/53-week fiscal calendar, where every year ends on
+// the same day of the week, and where each quarter consists of three periods
+// with a number of weeks given by the pattern. In years with 53 weeks,
+// the extra week is added to one of the periods.
+type FiscalCalendar struct {
+	Pattern         FiscalPattern
+	Rule            YearEndRule
+	EndWeekday      time.Weekday // the last day of the fiscal year, like time.Saturday
+	EndMonth        time.Month   // the month the fiscal year ends in
+	EndDay          int          // the day of the month, for NearestWeekdayToDate
+	ExtraWeekPeriod int          // the period (1-12) that gets the 53rd week, 0 is period 12
+	YearOffset      int          // added to the calendar year the fiscal year ends in, to number it
+}
+
+// A FiscalDate is a date in a fiscal calendar
+type FiscalDate struct {
+	Year    int // the fiscal year
+	Quarter int // 1 to 4
+	Period  int // 1 to 12
+	Week    int // 1 to 53, counting from the start of the fiscal year
+}
+
+// Create a new retail fiscal calendar with the 4-4-5 pattern, where the year
+// ends on the last Saturday of January. The fiscal year is numbered by the
+// calendar year it starts in, so fiscal 2024 ends in January 2025. For the
+// NRF calendar, where the year ends on the Saturday nearest to the end of
+// January, set Rule to NearestWeekdayToDate and EndDay to 31.
+func NewRetailFiscalCalendar() FiscalCalendar {
+	return FiscalCalendar{
+		Pattern:    Pattern445,
+		Rule:       LastWeekdayOfMonth,
+		EndWeekday: time.Saturday,
+		EndMonth:   time.January,
+		YearOffset: -1,
+	}
+}
+
+// Find the last day of the fiscal year that ends in the given calendar year
+func (fc FiscalCalendar) yearEnd(year int) time.Time {
+	if fc.Rule == NearestWeekdayToDate {
+		date := time.Date(year, fc.EndMonth, fc.EndDay, 0, 0, 0, 0, time.UTC)
+		diff := (int(fc.EndWeekday) - int(date.Weekday()) + 7) % 7
+		if diff > 3 {
+			diff -= 7
+		}
+		return date.AddDate(0, 0, diff)
+	}
+	return lastDayOfMonth(time.Date(year, fc.EndMonth, 1, 0, 0, 0, 0, time.UTC), fc.EndWeekday)
+}
+
+// YearRange returns the first and the last day of the given fiscal year
+func (fc FiscalCalendar) YearRange(fiscalYear int) (time.Time, time.Time) {
+	year := fiscalYear - fc.YearOffset
+	return fc.yearEnd(year-1).AddDate(0, 0, 1), fc.yearEnd(year)
+}
+
+// Weeks returns the number of weeks in the given fiscal year, 52 or 53
+func (fc FiscalCalendar) Weeks(fiscalYear int) int {
+	start, end := fc.YearRange(fiscalYear)
+	return (actualDays(start, end) + 1) / 7
+}
+
+// The number of weeks in each period of the given fiscal year
+func (fc FiscalCalendar) periodWeeks(fiscalYear int) [12]int {
+	var weeks [12]int
+	for i := range weeks {
+		weeks[i] = fc.Pattern[i%3]
+	}
+	if fc.Weeks(fiscalYear) == 53 {
+		extra := fc.ExtraWeekPeriod
+		if extra < 1 || extra > 12 {
+			extra = 12
+		}
+		weeks[extra-1]++
+	}
+	return weeks
+}
+
+// Date finds the fiscal year, quarter, period and week for a given date
+func (fc FiscalCalendar) Date(date time.Time) FiscalDate {
+	year, month, day := date.Date()
+	date = time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	// The fiscal year usually ends in this calendar year or the next, but a year
+	// that ends nearest to the end of December may end early in January
+	fiscalYear := year + fc.YearOffset
+	if date.After(fc.yearEnd(year)) {
+		fiscalYear++
+	} else if !date.After(fc.yearEnd(year - 1)) {
+		fiscalYear--
+	}
+	start, _ := fc.YearRange(fiscalYear)
+	week := actualDays(start, date)/7 + 1
+	period, weeks := 1, 0
+	for i, w := range fc.periodWeeks(fiscalYear) {
+		weeks += w
+		if week <= weeks {
+			period = i + 1
+			break
+		}
+	}
+	return FiscalDate{Year: fiscalYear, Quarter: (period-1)/3 + 1, Period: period, Week: week}
+}
+
+// PeriodRange returns the first and the last day of a period (1-12) in a fiscal year
+func (fc FiscalCalendar) PeriodRange(fiscalYear, period int) (time.Time, time.Time, error) {
+	if period < 1 || period > 12 {
+		return time.Time{}, time.Time{}, errors.New("the period must be from 1 to 12")
+	}
+	start, _ := fc.YearRange(fiscalYear)
+	weeks := fc.periodWeeks(fiscalYear)
+	for i := 0; i < period-1; i++ {
+		start = start.AddDate(0, 0, 7*weeks[i])
+	}
+	return start, start.AddDate(0, 0, 7*weeks[period-1]-1), nil
+}
+
+// QuarterRange returns the first and the last day of a quarter (1-4) in a fiscal year
+func (fc FiscalCalendar) QuarterRange(fiscalYear, quarter int) (time.Time, time.Time, error) {
+	if quarter < 1 || quarter > 4 {
+		return time.Time{}, time.Time{}, errors.New("the quarter must be from 1 to 4")
+	}
+	start, _, _ := fc.PeriodRange(fiscalYear, 3*quarter-2)
+	_, end, _ := fc.PeriodRange(fiscalYear, 3*quarter)
+	return start, end, nil
+}
+
+// WeekRange returns the first and the last day of a week (1-53) in a fiscal year
+func (fc FiscalCalendar) WeekRange(fiscalYear, week int) (time.Time, time.Time, error) {
+	if week < 1 || week > fc.Weeks(fiscalYear) {
+		return time.Time{}, time.Time{}, errors.New("no such week in the fiscal year")
+	}
+	start, _ := fc.YearRange(fiscalYear)
+	start = start.AddDate(0, 0, 7*(week-1))
+	return start, start.AddDate(0, 0, 6), nil
+}
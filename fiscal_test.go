@@ -0,0 +1,74 @@
+package kal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFiscalCalendar(t *testing.T) {
+	fc := NewRetailFiscalCalendar()
+
+	// Fiscal 2024 ends on the last Saturday of January 2025, and fiscal 2025
+	// has 53 weeks, to 31 January 2026
+	start, end := fc.YearRange(2024)
+	if !start.Equal(date(2024, time.January, 28)) || !end.Equal(date(2025, time.January, 25)) {
+		t.Errorf("YearRange(2024): got %s - %s", start, end)
+	}
+	if fc.Weeks(2024) != 52 || fc.Weeks(2025) != 53 {
+		t.Errorf("Weeks: got %d and %d, want 52 and 53", fc.Weeks(2024), fc.Weeks(2025))
+	}
+	if got, want := fc.Date(date(2025, time.January, 26)), (FiscalDate{2025, 1, 1, 1}); got != want {
+		t.Errorf("Date: got %+v, want %+v", got, want)
+	}
+
+	// In the NRF calendar, fiscal 2023 had 53 weeks, from 29 January 2023 to 3 February 2024
+	nrf := fc
+	nrf.Rule, nrf.EndDay = NearestWeekdayToDate, 31
+	start, end = nrf.YearRange(2023)
+	if !start.Equal(date(2023, time.January, 29)) || !end.Equal(date(2024, time.February, 3)) {
+		t.Errorf("YearRange(2023): got %s - %s", start, end)
+	}
+	if nrf.Weeks(2023) != 53 || nrf.Weeks(2024) != 52 {
+		t.Errorf("Weeks: got %d and %d, want 53 and 52", nrf.Weeks(2023), nrf.Weeks(2024))
+	}
+	if got, want := nrf.Date(date(2024, time.February, 3)), (FiscalDate{2023, 4, 12, 53}); got != want {
+		t.Errorf("Date: got %+v, want %+v", got, want)
+	}
+	if got, want := nrf.Date(date(2024, time.February, 4)), (FiscalDate{2024, 1, 1, 1}); got != want {
+		t.Errorf("Date: got %+v, want %+v", got, want)
+	}
+	// The third period of a 4-4-5 quarter has five weeks
+	start, end, err := nrf.PeriodRange(2024, 3)
+	if err != nil || !start.Equal(date(2024, time.March, 31)) || !end.Equal(date(2024, time.May, 4)) {
+		t.Errorf("PeriodRange(2024, 3): got %s - %s, %v", start, end, err)
+	}
+	for d := date(2020, time.January, 1); d.Year() < 2030; d = d.AddDate(0, 0, 1) {
+		fd := fc.Date(d)
+		start, end, err := fc.WeekRange(fd.Year, fd.Week)
+		if err != nil || d.Before(start) || d.After(end) {
+			t.Fatalf("WeekRange for %s (%+v): got %s - %s, %v", d, fd, start, end, err)
+		}
+		start, end, _ = fc.QuarterRange(fd.Year, fd.Quarter)
+		if d.Before(start) || d.After(end) {
+			t.Fatalf("QuarterRange for %s (%+v): got %s - %s", d, fd, start, end)
+		}
+	}
+
+	// Last Saturday of August
+	fc = FiscalCalendar{Pattern: Pattern544, Rule: LastWeekdayOfMonth, EndWeekday: time.Saturday, EndMonth: time.August}
+	if _, end := fc.YearRange(2025); !end.Equal(date(2025, time.August, 30)) {
+		t.Errorf("LastWeekdayOfMonth: got %s", end)
+	}
+
+	// Saturday nearest to 31 August
+	fc = FiscalCalendar{Pattern: Pattern454, Rule: NearestWeekdayToDate, EndWeekday: time.Saturday, EndMonth: time.August, EndDay: 31}
+	if _, end := fc.YearRange(2025); !end.Equal(date(2025, time.August, 30)) {
+		t.Errorf("NearestWeekdayToDate: got %s", end)
+	}
+
+	// Saturday nearest to 31 December, which may be in January
+	fc = FiscalCalendar{Pattern: Pattern445, Rule: NearestWeekdayToDate, EndWeekday: time.Saturday, EndMonth: time.December, EndDay: 31}
+	if got := fc.Date(date(2022, time.January, 1)); got.Year != 2021 || got.Week != 52 {
+		t.Errorf("Date: got %+v, want week 52 of 2021", got)
+	}
+}
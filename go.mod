@@ -5,15 +5,18 @@ go 1.24.4
 toolchain go1.24.5
 
 require (
+	github.com/emersion/go-webdav v0.7.0
 	github.com/xyproto/env v1.9.1
 	github.com/xyproto/vt v1.2.8
 )
 
 require (
+	github.com/emersion/go-ical v0.0.0-20240127095438-fc1c9d8fb2b6 // indirect
 	github.com/mattn/go-colorable v0.1.14 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/mgutz/ansi v0.0.0-20200706080929-d51e80ef957d // indirect
 	github.com/pkg/term v1.2.0-beta.2.0.20210419004637-f749b98bd0ba // indirect
+	github.com/teambition/rrule-go v1.8.2 // indirect
 	github.com/xyproto/burnfont v1.2.3 // indirect
 	github.com/xyproto/env/v2 v2.5.3 // indirect
 	golang.org/x/sys v0.34.0 // indirect
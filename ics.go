@@ -0,0 +1,160 @@
+package kal
+
+// Exporting the red days and notable days of a calendar to iCalendar (RFC 5545)
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ICSOptions are the options for WriteICS
+type ICSOptions struct {
+	Name    string    // the name of the calendar, as shown by calendar applications
+	Domain  string    // the domain part of the UIDs of the events, "kal" if empty
+	RedOnly bool      // leave out the notable days
+	Stamp   time.Time // the DTSTAMP of the events, icsStamp if zero, so that the output is stable
+}
+
+// The DTSTAMP of the events when no stamp is given. It is fixed, so that the
+// output only changes when the days change.
+var icsStamp = time.Unix(0, 0).UTC()
+
+// The categories of the events
+const (
+	icsCategoryRed     = "HOLIDAY"
+	icsCategoryNotable = "NOTABLE"
+	icsCategoryFlag    = "FLAGDAY"
+)
+
+// The maximum length of a line, in octets, not counting the line break
+const icsLineLength = 75
+
+// Escape the special characters of a TEXT value
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// Fold a content line so that no line is longer than 75 octets, without
+// splitting UTF-8 sequences. The lines end with CRLF, and the continuation
+// lines start with a space.
+func icsFold(line string) string {
+	var sb strings.Builder
+	limit := icsLineLength
+	for len(line) > limit {
+		i := limit
+		for i > 0 && !utf8.RuneStart(line[i]) {
+			i--
+		}
+		sb.WriteString(line[:i] + "\r\n ")
+		line = line[i:]
+		limit = icsLineLength - 1
+	}
+	sb.WriteString(line + "\r\n")
+	return sb.String()
+}
+
+// Format a date as an iCalendar DATE value
+func icsDate(date time.Time) string {
+	return date.Format("20060102")
+}
+
+// An ICSEvent is a red day or a notable day, as an event in an iCalendar file
+type ICSEvent struct {
+	SpecialDay
+	UID string // the UID of the event, without the domain
+}
+
+// ICSEvents returns the red days and notable days of the calendar, from
+// and including the date of from, to and including the date of to, as
+// events. The UID of an event is made from the identifier of the day and
+// the year, like "constitution_day-2025", or from the identifier and the
+// date if the identifier is used more than once in the same year. The UIDs
+// do not depend on from and to, so that calendar applications can update
+// a subscription without duplicating events.
+func ICSEvents(cal Calendar, from, to time.Time, opts ICSOptions) []ICSEvent {
+	var events []ICSEvent
+	first, last := dateSpan(from, to, time.UTC)
+	for year := from.Year(); year <= to.Year(); year++ {
+		// Look at the whole year, to find the identifiers that are used more than once
+		days := SpecialDays(cal, time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC))
+		count := make(map[string]int)
+		for _, sd := range days {
+			count[sd.ID]++
+		}
+		for _, sd := range days {
+			if sd.Date.Before(first) || sd.Date.After(last) || (opts.RedOnly && !sd.Red) {
+				continue
+			}
+			uid := sd.ID + "-" + strconv.Itoa(year)
+			if count[sd.ID] > 1 {
+				uid = sd.ID + "-" + icsDate(sd.Date)
+			}
+			events = append(events, ICSEvent{sd, uid})
+		}
+	}
+	return events
+}
+
+// Write a single event as a VEVENT
+func (e ICSEvent) write(sb *strings.Builder, opts ICSOptions) {
+	domain := opts.Domain
+	if domain == "" {
+		domain = "kal"
+	}
+	stamp := opts.Stamp
+	if stamp.IsZero() {
+		stamp = icsStamp
+	}
+	categories := []string{icsCategoryNotable}
+	transp, busy := "TRANSPARENT", "FREE"
+	if e.Red {
+		categories[0] = icsCategoryRed
+		transp, busy = "OPAQUE", "OOF"
+	}
+	if e.Flag {
+		categories = append(categories, icsCategoryFlag)
+	}
+	for _, line := range []string{
+		"BEGIN:VEVENT",
+		"UID:" + e.UID + "@" + domain,
+		"DTSTAMP:" + stamp.UTC().Format("20060102T150405Z"),
+		"DTSTART;VALUE=DATE:" + icsDate(e.Date),
+		"DTEND;VALUE=DATE:" + icsDate(e.Date.AddDate(0, 0, 1)),
+		"SUMMARY:" + icsEscape(e.Name),
+		"CATEGORIES:" + strings.Join(categories, ","),
+		"TRANSP:" + transp,
+		"X-MICROSOFT-CDO-BUSYSTATUS:" + busy,
+		"X-MICROSOFT-CDO-ALLDAYEVENT:TRUE",
+		"END:VEVENT",
+	} {
+		sb.WriteString(icsFold(line))
+	}
+}
+
+// WriteICSEvents writes the given events as an iCalendar file, with one
+// all-day event per day. Red days are marked as busy and notable days as free.
+func WriteICSEvents(w io.Writer, events []ICSEvent, opts ICSOptions) error {
+	var sb strings.Builder
+	for _, line := range []string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//xyproto//kal//EN", "CALSCALE:GREGORIAN", "METHOD:PUBLISH"} {
+		sb.WriteString(icsFold(line))
+	}
+	if opts.Name != "" {
+		sb.WriteString(icsFold("X-WR-CALNAME:" + icsEscape(opts.Name)))
+	}
+	for _, e := range events {
+		e.write(&sb, opts)
+	}
+	sb.WriteString(icsFold("END:VCALENDAR"))
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteICS writes the red days and notable days of the calendar, from and
+// including the date of from, to and including the date of to, as an
+// iCalendar file. See ICSEvents for how the UIDs are made.
+func WriteICS(w io.Writer, cal Calendar, from, to time.Time, opts ICSOptions) error {
+	return WriteICSEvents(w, ICSEvents(cal, from, to, opts), opts)
+}
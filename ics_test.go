@@ -0,0 +1,56 @@
+package kal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteICS(t *testing.T) {
+	cal := NewNorwegianCalendar()
+	var sb strings.Builder
+	if err := WriteICS(&sb, cal, date(2025, time.May, 17), date(2025, time.June, 7), ICSOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	ics := sb.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:constitution_day-2025@kal\r\nDTSTAMP:19700101T000000Z\r\nDTSTART;VALUE=DATE:20250517\r\nDTEND;VALUE=DATE:20250518\r\nSUMMARY:Grunnlovsdagen\r\nCATEGORIES:HOLIDAY,FLAGDAY\r\nTRANSP:OPAQUE\r\n",
+		"SUMMARY:Unionsoppløsningen med Sverige i 1905\r\nCATEGORIES:NOTABLE,FLAGDAY\r\nTRANSP:TRANSPARENT\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("missing %q in:\n%s", want, ics)
+		}
+	}
+	if n := strings.Count(ics, "BEGIN:VEVENT"); n != 3 {
+		t.Errorf("got %d events, want 3", n)
+	}
+
+	// The UIDs do not depend on the range, and an identifier that is used
+	// more than once in a year also gets the date
+	events := ICSEvents(cal, date(2025, time.June, 9), date(2025, time.June, 30), ICSOptions{RedOnly: true})
+	if len(events) != 1 || events[0].UID != "whit_monday-2025" {
+		t.Errorf("got %+v", events)
+	}
+	rec, err := ParseRecurrence(date(2025, time.March, 5), "RRULE:FREQ=MONTHLY;COUNT=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	events = ICSEvents(NewEventCalendar(nil, Event{ID: "review", Name: "Review", Recurrence: rec}), date(2025, time.January, 1), date(2025, time.December, 31), ICSOptions{})
+	if len(events) != 2 || events[0].UID != "review-20250305" || events[1].UID != "review-20250405" {
+		t.Errorf("got %+v", events)
+	}
+
+	// Long lines are folded at 75 octets, without splitting characters
+	line := "SUMMARY:" + strings.Repeat("æ", 80)
+	folded := icsFold(line)
+	for _, l := range strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n") {
+		if len(l) > 75 || !strings.ContainsRune("S æ", []rune(l)[0]) {
+			t.Errorf("bad folded line %q", l)
+		}
+	}
+	if strings.ReplaceAll(folded, "\r\n ", "") != line+"\r\n" {
+		t.Errorf("unfolding gives %q", folded)
+	}
+}
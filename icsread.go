@@ -0,0 +1,717 @@
+package kal
+
+// Importing events from iCalendar (RFC 5545) files into a Calendar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ICSImportOptions are the options for ReadICS
+type ICSImportOptions struct {
+	Calendar Calendar       // the calendar with the other days, a calendar with English names and no special days if nil
+	RedDays  []string       // categories or summaries that make an event a red day, HOLIDAY if nil
+	FlagDays []string       // categories or summaries that make an event a flag day, FLAGDAY if nil
+	Location *time.Location // the time zone of the dates of timed events in UTC or in floating time, time.Local if nil
+}
+
+// An icsProperty is a single content line, like "DTSTART;TZID=Europe/Oslo:20250101T090000"
+type icsProperty struct {
+	name   string            // the name, in upper case
+	params map[string]string // the parameters, with the names in upper case and without quotes
+	value  string
+}
+
+// Parse a content line into the name, the parameters and the value
+func parseICSProperty(line string) (icsProperty, error) {
+	p := icsProperty{params: make(map[string]string)}
+	// Find the separators of the parameters and the value, outside of quotes
+	var separators []int
+	quoted := false
+	for i := 0; i < len(line); i++ {
+		if line[i] == '"' {
+			quoted = !quoted
+		} else if !quoted && (line[i] == ';' || line[i] == ':') {
+			separators = append(separators, i)
+			if line[i] == ':' {
+				break
+			}
+		}
+	}
+	if len(separators) == 0 || line[separators[len(separators)-1]] != ':' || separators[0] == 0 {
+		return p, errors.New("Invalid line: " + line)
+	}
+	p.name = strings.ToUpper(line[:separators[0]])
+	for i := 1; i < len(separators); i++ {
+		param := line[separators[i-1]+1 : separators[i]]
+		eq := strings.Index(param, "=")
+		if eq <= 0 {
+			return p, errors.New("Invalid parameter: " + param)
+		}
+		p.params[strings.ToUpper(param[:eq])] = strings.ReplaceAll(param[eq+1:], `"`, "")
+	}
+	p.value = line[separators[len(separators)-1]+1:]
+	return p, nil
+}
+
+// Undo the escaping of a TEXT value
+func icsUnescape(s string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n").Replace(s)
+}
+
+// Split a list of TEXT values, like CATEGORIES, on the commas that are not escaped
+func icsSplitText(s string) []string {
+	var values []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			values = append(values, icsUnescape(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(values, icsUnescape(s[start:]))
+}
+
+// Parse a duration like P1D, PT1H30M or P2W. Negative durations are not supported.
+func parseICSDuration(value string) (time.Duration, error) {
+	s := strings.TrimPrefix(value, "+")
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, errors.New("Invalid duration: " + value)
+	}
+	var (
+		d      time.Duration
+		n      int
+		digits bool
+		inTime bool
+	)
+	for _, r := range s[1:] {
+		var unit time.Duration
+		switch {
+		case r >= '0' && r <= '9':
+			n = n*10 + int(r-'0')
+			digits = true
+			continue
+		case r == 'T' && !inTime && !digits:
+			inTime = true
+			continue
+		case r == 'W' && !inTime:
+			unit = 7 * 24 * time.Hour
+		case r == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case r == 'H' && inTime:
+			unit = time.Hour
+		case r == 'M' && inTime:
+			unit = time.Minute
+		case r == 'S' && inTime:
+			unit = time.Second
+		}
+		if unit == 0 || !digits {
+			return 0, errors.New("Invalid duration: " + value)
+		}
+		d += time.Duration(n) * unit
+		n, digits = 0, false
+	}
+	if digits {
+		return 0, errors.New("Invalid duration: " + value)
+	}
+	return d, nil
+}
+
+// Parse a UTC offset like +0100, -0500 or +053000, returning the number of seconds
+func parseUTCOffset(value string) (int, error) {
+	if (len(value) != 5 && len(value) != 7) || (value[0] != '+' && value[0] != '-') {
+		return 0, errors.New("Invalid UTC offset: " + value)
+	}
+	seconds := 0
+	for i, unit := range []int{3600, 60, 1} {
+		if 1+2*i >= len(value) {
+			break
+		}
+		n, err := strconv.Atoi(value[1+2*i : 3+2*i])
+		if err != nil {
+			return 0, errors.New("Invalid UTC offset: " + value)
+		}
+		seconds += n * unit
+	}
+	if value[0] == '-' {
+		seconds = -seconds
+	}
+	return seconds, nil
+}
+
+// A tzTransition is a change of the UTC offset in a time zone
+type tzTransition struct {
+	at     int64 // Unix time of the change
+	offset int   // the new UTC offset, in seconds
+	dst    bool
+	name   string
+}
+
+// The last time that the changes of a time zone from a VTIMEZONE are
+// listed for. Later changes are described by a POSIX TZ string.
+var maxTZTransition = time.Date(2038, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// A tzRule is an observance that recurs every year without an end, like
+// the start of daylight saving time
+type tzRule struct {
+	zone  tzTransition
+	start time.Time // the first onset, in the local time before the change
+	rrule RRule
+}
+
+// Format a number of seconds as the offset or time of a POSIX TZ string, like "-1" or "2:30"
+func posixDuration(seconds int) string {
+	sign := ""
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	s := sign + strconv.Itoa(seconds/3600)
+	if seconds%3600 != 0 {
+		s += fmt.Sprintf(":%02d", seconds/60%60)
+		if seconds%60 != 0 {
+			s += fmt.Sprintf(":%02d", seconds%60)
+		}
+	}
+	return s
+}
+
+// Format a zone as the name and the offset of a POSIX TZ string, like
+// "CET-1". The sign of the offset is the opposite of the UTC offset.
+// Names that are not three letters or more are replaced by the offset.
+func posixZone(t tzTransition) string {
+	name := t.name
+	if len(name) < 3 || strings.Trim(name, "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz") != "" {
+		sign, offset := "+", t.offset
+		if offset < 0 {
+			sign, offset = "-", -offset
+		}
+		name = fmt.Sprintf("<%s%02d%02d>", sign, offset/3600, offset/60%60)
+	}
+	return name + posixDuration(-t.offset)
+}
+
+// Format the onsets of the rule as the date and time of a POSIX TZ string,
+// like "M3.5.0/2" for the last Sunday of March at 02:00. Returns false for
+// rules that are not on the nth or last weekday of a month.
+func (r tzRule) posix() (string, bool) {
+	rr := r.rrule
+	if rr.Freq != Yearly || rr.Interval != 1 || len(rr.ByMonth) != 1 || len(rr.ByDay) != 1 || len(rr.ByMonthDay) > 0 || len(rr.BySetPos) > 1 {
+		return "", false
+	}
+	n := rr.ByDay[0].N
+	if n == 0 && len(rr.BySetPos) == 1 {
+		n = rr.BySetPos[0]
+	} else if len(rr.BySetPos) > 0 {
+		return "", false
+	}
+	if n == -1 {
+		n = 5
+	}
+	if n < 1 || n > 5 {
+		return "", false
+	}
+	clock := r.start.Hour()*3600 + r.start.Minute()*60 + r.start.Second()
+	return fmt.Sprintf("M%d.%d.%d/%s", rr.ByMonth[0], n, rr.ByDay[0].Weekday, posixDuration(clock)), true
+}
+
+// The POSIX TZ string for the changes after the last transition, from the
+// standard and daylight observances that recur without an end. Returns
+// false if the observances recur in a way that can not be described.
+func posixTZ(std, dst *tzRule) (string, bool) {
+	switch {
+	case std == nil && dst == nil:
+		return "", true
+	case dst == nil:
+		return posixZone(std.zone), true
+	case std == nil:
+		return posixZone(dst.zone), true
+	}
+	start, ok := dst.posix()
+	if !ok {
+		return "", false
+	}
+	end, ok := std.posix()
+	if !ok {
+		return "", false
+	}
+	return posixZone(std.zone) + posixZone(dst.zone) + "," + start + "," + end, true
+}
+
+// Encode the transitions as TZif version 2 data, as described in RFC 8536,
+// with the given POSIX TZ string as the footer. The first zone is the zone
+// before the first transition. The version 1 data block only has that zone.
+func tzifData(transitions []tzTransition, before tzTransition, footer string) []byte {
+	type zoneType struct {
+		offset int
+		dst    bool
+		name   string
+	}
+	var (
+		types   []zoneType
+		abbrevs []byte
+		indices []byte
+	)
+	typeIndex := func(t tzTransition) byte {
+		zt := zoneType{t.offset, t.dst, t.name}
+		for i, existing := range types {
+			if existing == zt {
+				return byte(i)
+			}
+		}
+		types = append(types, zt)
+		return byte(len(types) - 1)
+	}
+	typeIndex(before)
+	for _, t := range transitions {
+		indices = append(indices, typeIndex(t))
+	}
+	var buf bytes.Buffer
+	header := func(transitionCount, typeCount, charCount int) {
+		buf.WriteString("TZif2")
+		buf.Write(make([]byte, 15))
+		for _, n := range []int{0, 0, 0, transitionCount, typeCount, charCount} {
+			binary.Write(&buf, binary.BigEndian, uint32(n))
+		}
+	}
+	zone := func(zt zoneType, abbrevIndex int) {
+		binary.Write(&buf, binary.BigEndian, int32(zt.offset))
+		dst := byte(0)
+		if zt.dst {
+			dst = 1
+		}
+		buf.Write([]byte{dst, byte(abbrevIndex)})
+	}
+	header(0, 1, len(types[0].name)+1)
+	zone(types[0], 0)
+	buf.WriteString(types[0].name + "\x00")
+	for _, zt := range types {
+		abbrevs = append(append(abbrevs, zt.name...), 0)
+	}
+	header(len(transitions), len(types), len(abbrevs))
+	for _, t := range transitions {
+		binary.Write(&buf, binary.BigEndian, t.at)
+	}
+	buf.Write(indices)
+	abbrevIndex := 0
+	for _, zt := range types {
+		zone(zt, abbrevIndex)
+		abbrevIndex += len(zt.name) + 1
+	}
+	buf.Write(abbrevs)
+	buf.WriteString("\n" + footer + "\n")
+	return buf.Bytes()
+}
+
+// An icsComponent is a component with its properties, like a VEVENT, or
+// a STANDARD or DAYLIGHT observance in a VTIMEZONE
+type icsComponent struct {
+	name       string
+	properties []icsProperty
+	children   []*icsComponent
+}
+
+// Find the first property with the given name
+func (c *icsComponent) property(name string) (icsProperty, bool) {
+	for _, p := range c.properties {
+		if p.name == name {
+			return p, true
+		}
+	}
+	return icsProperty{}, false
+}
+
+// Create a time zone from a VTIMEZONE component, with the changes of the
+// UTC offset from the STANDARD and DAYLIGHT observances. The changes after
+// 2038 are described by a POSIX TZ string, so the observances that recur
+// without an end must be on the nth or last weekday of a month.
+func icsTimeZone(tzid string, vtimezone *icsComponent) (*time.Location, error) {
+	var (
+		transitions []tzTransition
+		std, dst    *tzRule
+	)
+	for _, observance := range vtimezone.children {
+		prop, ok := observance.property("DTSTART")
+		if !ok {
+			return nil, errors.New("Missing DTSTART in time zone: " + tzid)
+		}
+		// The onsets are in the local time before the change, read here as UTC
+		start, _, err := parseICSTime(prop.value, time.UTC)
+		if err != nil {
+			return nil, errors.New("Invalid DTSTART in time zone: " + tzid)
+		}
+		t := tzTransition{dst: observance.name == "DAYLIGHT"}
+		var from int
+		for _, name := range []string{"TZOFFSETFROM", "TZOFFSETTO"} {
+			prop, ok := observance.property(name)
+			if !ok {
+				return nil, errors.New("Missing " + name + " in time zone: " + tzid)
+			}
+			offset, err := parseUTCOffset(prop.value)
+			if err != nil {
+				return nil, err
+			}
+			if name == "TZOFFSETFROM" {
+				from = offset
+			} else {
+				t.offset = offset
+			}
+		}
+		if prop, ok := observance.property("TZNAME"); ok {
+			t.name = icsUnescape(prop.value)
+		}
+		onsets := []time.Time{start}
+		for _, prop := range observance.properties {
+			switch prop.name {
+			case "RRULE":
+				r, err := ParseRRule(prop.value, start)
+				if err != nil {
+					return nil, err
+				}
+				onsets = append(onsets, r.Between(start, maxTZTransition)...)
+				if r.Count == 0 && r.Until.IsZero() {
+					// Keep the latest of the standard and of the daylight observances
+					last := &std
+					if t.dst {
+						last = &dst
+					}
+					if *last == nil || (*last).start.Before(start) {
+						*last = &tzRule{t, start, r}
+					}
+				}
+			case "RDATE":
+				dates, _, err := parseDateProperty("RDATE:"+prop.value, time.UTC)
+				if err != nil {
+					return nil, err
+				}
+				onsets = append(onsets, dates...)
+			}
+		}
+		for _, onset := range onsets {
+			t.at = onset.Unix() - int64(from)
+			if onset.Before(maxTZTransition) {
+				transitions = append(transitions, t)
+			}
+		}
+	}
+	if len(transitions) == 0 {
+		return nil, errors.New("No observances in time zone: " + tzid)
+	}
+	sort.SliceStable(transitions, func(i, j int) bool { return transitions[i].at < transitions[j].at })
+	// Remove duplicates, like DTSTART that is also found by RRULE
+	unique := transitions[:1]
+	for _, t := range transitions[1:] {
+		if t.at != unique[len(unique)-1].at {
+			unique = append(unique, t)
+		}
+	}
+	// Before the first change, the time zone has the offset it changes from
+	first := unique[0]
+	before := tzTransition{offset: first.offset, dst: !first.dst}
+	for _, t := range unique {
+		if t.dst != first.dst {
+			before = tzTransition{offset: t.offset, dst: t.dst, name: t.name}
+			break
+		}
+	}
+	footer, ok := posixTZ(std, dst)
+	if !ok {
+		return nil, errors.New("Unsupported recurrence after 2038 in time zone: " + tzid)
+	}
+	return time.LoadLocationFromTZData(tzid, tzifData(unique, before, footer))
+}
+
+// An icsReader converts the parsed components of an iCalendar file to events
+type icsReader struct {
+	zones map[string]*time.Location // the time zones from VTIMEZONE, by TZID
+	loc   *time.Location            // the time zone for UTC and floating times
+	opts  ICSImportOptions
+}
+
+// Find the time zone of a property, from the TZID parameter
+func (ir *icsReader) location(p icsProperty) (*time.Location, error) {
+	tzid, ok := p.params["TZID"]
+	if !ok {
+		return ir.loc, nil
+	}
+	if loc, ok := ir.zones[tzid]; ok {
+		return loc, nil
+	}
+	loc, err := time.LoadLocation(strings.TrimPrefix(tzid, "/"))
+	if err != nil {
+		return nil, errors.New("Unknown time zone: " + tzid)
+	}
+	return loc, nil
+}
+
+// Parse the dates of a property like DTSTART, RDATE or EXDATE.
+// Returns true if the values are dates without a time of day, and true
+// if the values are given in UTC.
+func (ir *icsReader) dates(p icsProperty) ([]time.Time, bool, bool, error) {
+	if value := strings.ToUpper(p.params["VALUE"]); value != "" && value != "DATE" && value != "DATE-TIME" {
+		return nil, false, false, errors.New("Unsupported value type for " + p.name + ": " + value)
+	}
+	loc, err := ir.location(p)
+	if err != nil {
+		return nil, false, false, err
+	}
+	var (
+		dates    []time.Time
+		dateOnly bool
+	)
+	for _, value := range strings.Split(p.value, ",") {
+		t, isDate, err := parseICSTime(value, loc)
+		if err != nil {
+			return nil, false, false, errors.New("Invalid date in " + p.name + ": " + value)
+		}
+		if isDate {
+			// Dates are dates in any time zone
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		}
+		dates = append(dates, t)
+		dateOnly = isDate
+	}
+	return dates, dateOnly, strings.HasSuffix(p.value, "Z"), nil
+}
+
+// Checks if one of the given names is in the list, ignoring case
+func icsMatch(list []string, names ...string) bool {
+	for _, s := range list {
+		for _, name := range names {
+			if strings.EqualFold(strings.TrimSpace(s), strings.TrimSpace(name)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Convert a VEVENT to an event. Returns false if the event is cancelled.
+func (ir *icsReader) event(vevent *icsComponent) (Event, bool, error) {
+	var (
+		e          Event
+		names      []string // the categories and the summary
+		dtstart    time.Time
+		hasStart   bool
+		dateOnly   bool
+		utc        bool
+		dtend      time.Time
+		hasEnd     bool
+		rrules     []string
+		rdates     []time.Time
+		exdates    []time.Time
+		exdays     []time.Time
+		hasDurProp bool
+	)
+	for _, p := range vevent.properties {
+		switch p.name {
+		case "UID":
+			e.ID = p.value
+		case "SUMMARY":
+			e.Name = icsUnescape(p.value)
+			names = append(names, e.Name)
+		case "CATEGORIES":
+			names = append(names, icsSplitText(p.value)...)
+		case "STATUS":
+			if strings.EqualFold(p.value, "CANCELLED") {
+				return e, false, nil
+			}
+		case "DTSTART", "DTEND", "RDATE", "EXDATE":
+			dates, isDate, isUTC, err := ir.dates(p)
+			if err != nil {
+				return e, false, err
+			}
+			switch p.name {
+			case "DTSTART":
+				dtstart, hasStart, dateOnly, utc = dates[0], true, isDate, isUTC
+			case "DTEND":
+				dtend, hasEnd = dates[0], true
+			case "RDATE":
+				rdates = append(rdates, dates...)
+			case "EXDATE":
+				if isDate {
+					exdays = append(exdays, dates...)
+				} else {
+					exdates = append(exdates, dates...)
+				}
+			}
+		case "DURATION":
+			d, err := parseICSDuration(p.value)
+			if err != nil {
+				return e, false, err
+			}
+			e.Duration, hasDurProp = d, true
+		case "RRULE":
+			rrules = append(rrules, p.value)
+		case "RECURRENCE-ID", "EXRULE":
+			return e, false, errors.New("Unsupported property in event " + e.ID + ": " + p.name)
+		}
+	}
+	if !hasStart {
+		return e, false, errors.New("Missing DTSTART in event: " + e.ID)
+	}
+	switch {
+	case hasEnd && hasDurProp:
+		return e, false, errors.New("Both DTEND and DURATION in event: " + e.ID)
+	case hasEnd:
+		if dtend.Before(dtstart) {
+			return e, false, errors.New("DTEND is before DTSTART in event: " + e.ID)
+		}
+		e.Duration = dtend.Sub(dtstart)
+	case !hasDurProp && dateOnly:
+		// An all-day event without an end lasts for one day
+		e.Duration = 24 * time.Hour
+	}
+	if utc && !dateOnly {
+		e.Location = ir.loc
+	}
+	rec := Recurrence{Dtstart: dtstart, RDates: rdates, ExDates: exdates, ExDays: exdays}
+	for _, rule := range rrules {
+		r, err := ParseRRule(rule, dtstart)
+		if err != nil {
+			return e, false, err
+		}
+		rec.RRules = append(rec.RRules, r)
+	}
+	e.Recurrence = rec
+	red, flag := ir.opts.RedDays, ir.opts.FlagDays
+	if red == nil {
+		red = []string{icsCategoryRed}
+	}
+	if flag == nil {
+		flag = []string{icsCategoryFlag}
+	}
+	e.Red = icsMatch(red, names...)
+	e.Flag = icsMatch(flag, names...)
+	return e, true, nil
+}
+
+// Read the components of an iCalendar file, after unfolding the lines
+func readICSComponents(r io.Reader) (*icsComponent, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.NewReplacer("\n ", "", "\n\t", "").Replace(text)
+	var (
+		root  *icsComponent
+		stack []*icsComponent
+		skip  int // the depth of the component that is skipped, or 0
+	)
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		p, err := parseICSProperty(line)
+		if err != nil {
+			return nil, err
+		}
+		switch p.name {
+		case "BEGIN":
+			name := strings.ToUpper(p.value)
+			parent := ""
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1].name
+			}
+			c := &icsComponent{name: name}
+			if skip == 0 {
+				switch {
+				case parent == "" && name == "VCALENDAR" && root == nil:
+					root = c
+				case parent == "VCALENDAR" && (name == "VEVENT" || name == "VTIMEZONE"):
+				case parent == "VTIMEZONE" && (name == "STANDARD" || name == "DAYLIGHT"):
+				case parent == "VEVENT" && name == "VALARM", strings.HasPrefix(name, "X-") && parent != "":
+					// Alarms and extensions do not change the days
+					skip = len(stack) + 1
+				case parent == "":
+					return nil, errors.New("Not an iCalendar file, found: BEGIN:" + name)
+				default:
+					return nil, errors.New("Unsupported component: " + name)
+				}
+				if len(stack) > 0 && skip == 0 {
+					stack[len(stack)-1].children = append(stack[len(stack)-1].children, c)
+				}
+			}
+			stack = append(stack, c)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].name != strings.ToUpper(p.value) {
+				return nil, errors.New("Unexpected END:" + p.value)
+			}
+			if skip == len(stack) {
+				skip = 0
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, errors.New("Property outside of VCALENDAR: " + p.name)
+			}
+			if skip == 0 {
+				stack[len(stack)-1].properties = append(stack[len(stack)-1].properties, p)
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("Not an iCalendar file, missing VCALENDAR")
+	}
+	if len(stack) > 0 {
+		return nil, errors.New("Missing END:" + stack[len(stack)-1].name)
+	}
+	return root, nil
+}
+
+// ReadICS reads an iCalendar file and returns a Calendar with the events in
+// it, on top of the calendar in the options. Recurring events, all-day and
+// timed events and events that last for several days are supported.
+// The dates of timed events are found in the time zone of the event.
+// An event is a red day if one of the categories or the summary is listed
+// in RedDays of the options, and a notable day otherwise. Cancelled events
+// and alarms are left out. Constructs that can not be imported correctly,
+// like RECURRENCE-ID or VTODO, give an error.
+func ReadICS(r io.Reader, opts ICSImportOptions) (EventCalendar, error) {
+	root, err := readICSComponents(r)
+	if err != nil {
+		return EventCalendar{}, err
+	}
+	ir := &icsReader{zones: make(map[string]*time.Location), loc: opts.Location, opts: opts}
+	if ir.loc == nil {
+		ir.loc = time.Local
+	}
+	for _, c := range root.children {
+		if c.name != "VTIMEZONE" {
+			continue
+		}
+		p, ok := c.property("TZID")
+		if !ok {
+			return EventCalendar{}, errors.New("Missing TZID in VTIMEZONE")
+		}
+		loc, err := icsTimeZone(p.value, c)
+		if err != nil {
+			return EventCalendar{}, err
+		}
+		ir.zones[p.value] = loc
+	}
+	var events []Event
+	for _, c := range root.children {
+		if c.name != "VEVENT" {
+			continue
+		}
+		e, ok, err := ir.event(c)
+		if err != nil {
+			return EventCalendar{}, err
+		}
+		if ok {
+			events = append(events, e)
+		}
+	}
+	return NewEventCalendar(opts.Calendar, events...), nil
+}
@@ -0,0 +1,142 @@
+package kal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testICS = `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Example//HR//EN
+BEGIN:VTIMEZONE
+TZID:W. Europe Standard Time
+BEGIN:STANDARD
+DTSTART:16010101T030000
+TZOFFSETFROM:+0200
+TZOFFSETTO:+0100
+RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=10
+END:STANDARD
+BEGIN:DAYLIGHT
+DTSTART:16010101T020000
+TZOFFSETFROM:+0100
+TZOFFSETTO:+0200
+RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=3
+END:DAYLIGHT
+END:VTIMEZONE
+BEGIN:VEVENT
+UID:day-off-1
+DTSTART;VALUE=DATE:20250502
+SUMMARY:Company day off
+CATEGORIES:Company holiday
+END:VEVENT
+BEGIN:VEVENT
+UID:summer-1
+DTSTART;VALUE=DATE:20250714
+DTEND;VALUE=DATE:20250719
+SUMMARY:Summer closure
+BEGIN:VALARM
+TRIGGER:-PT15M
+ACTION:DISPLAY
+END:VALARM
+END:VEVENT
+BEGIN:VEVENT
+UID:night-1
+DTSTART;TZID="W. Europe Standard Time":20250107T003000
+DURATION:PT1H
+RRULE:FREQ=WEEKLY;BYDAY=TU
+SUMMARY:Night deploy\, weekly
+END:VEVENT
+BEGIN:VEVENT
+UID:utc-1
+DTSTART:20250309T233000Z
+SUMMARY:Late call
+END:VEVENT
+BEGIN:VEVENT
+UID:cancelled-1
+DTSTART;VALUE=DATE:20250303
+SUMMARY:Cancelled party
+STATUS:CANCELLED
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestReadICS(t *testing.T) {
+	cal, err := ReadICS(strings.NewReader(testICS), ICSImportOptions{
+		RedDays:  []string{"company holiday", "Summer closure"},
+		Location: time.FixedZone("CET", 60*60),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		date    time.Time
+		red     bool
+		notable string
+	}{
+		{date(2025, time.May, 2), true, ""},
+		{date(2025, time.July, 14), true, ""},
+		{date(2025, time.July, 18), true, ""},
+		{date(2025, time.July, 19), false, ""},
+		// 00:30 on Tuesdays in the time zone of the event, in winter and in summer
+		{date(2025, time.January, 7), false, "Night deploy, weekly"},
+		{date(2025, time.July, 7), false, ""},
+		{date(2025, time.July, 8), false, "Night deploy, weekly"},
+		// 23:30 UTC is the next day in the time zone from the options
+		{date(2025, time.March, 10), false, "Late call"},
+		{date(2025, time.March, 3), false, ""},
+	} {
+		red, _, _ := cal.RedDay(tc.date)
+		_, notable, _ := cal.NotableDay(tc.date)
+		if red != tc.red || notable != tc.notable {
+			t.Errorf("%s: got %v %q, want %v %q", tc.date.Format("2006-01-02"), red, notable, tc.red, tc.notable)
+		}
+	}
+	night := cal.Events[2]
+	if _, offset := night.Recurrence.Dtstart.AddDate(0, 6, 0).Zone(); offset != 2*60*60 {
+		t.Errorf("got offset %d in summer, want 7200", offset)
+	}
+	// The changes after 2038 follow the rules of the observances
+	loc := night.Recurrence.Dtstart.Location()
+	for _, tc := range []struct {
+		t      time.Time
+		offset int
+	}{
+		{time.Date(2045, time.March, 26, 0, 59, 0, 0, time.UTC), 60 * 60},
+		{time.Date(2045, time.March, 26, 1, 0, 0, 0, time.UTC), 2 * 60 * 60},
+		{time.Date(2045, time.October, 29, 0, 59, 0, 0, time.UTC), 2 * 60 * 60},
+		{time.Date(2045, time.October, 29, 1, 0, 0, 0, time.UTC), 60 * 60},
+	} {
+		if _, offset := tc.t.In(loc).Zone(); offset != tc.offset {
+			t.Errorf("%s: got offset %d, want %d", tc.t, offset, tc.offset)
+		}
+	}
+
+	for _, bad := range []string{
+		strings.Replace(testICS, "BEGIN:VEVENT\nUID:day-off-1", "BEGIN:VTODO\nEND:VTODO\nBEGIN:VEVENT\nUID:day-off-1", 1),
+		strings.Replace(testICS, "UID:summer-1", "UID:summer-1\nRECURRENCE-ID;VALUE=DATE:20250714", 1),
+		strings.Replace(testICS, `TZID="W. Europe Standard Time"`, "TZID=Nowhere/Special", 1),
+		strings.Replace(testICS, "END:VCALENDAR", "", 1),
+		strings.Replace(testICS, "BYDAY=-1SU;BYMONTH=3", "BYMONTHDAY=25,26,27,28,29,30,31;BYDAY=SU;BYMONTH=3", 1),
+	} {
+		if _, err := ReadICS(strings.NewReader(bad), ICSImportOptions{}); err == nil {
+			t.Error("expected an error")
+		}
+	}
+
+	// Reading an exported calendar gives the same red days and flag days
+	var sb strings.Builder
+	if err := WriteICS(&sb, NewNorwegianCalendar(), date(2025, time.January, 1), date(2025, time.December, 31), ICSOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	imported, err := ReadICS(strings.NewReader(sb.String()), ICSImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if red, name, flag := imported.RedDay(date(2025, time.May, 17)); !red || name != "Grunnlovsdagen" || !flag {
+		t.Errorf("got %v %q %v for 17 May", red, name, flag)
+	}
+	if notable, name, _ := imported.NotableDay(date(2025, time.June, 7)); !notable || name != "Unionsoppløsningen med Sverige i 1905" {
+		t.Errorf("got %v %q for 7 June", notable, name)
+	}
+}
@@ -0,0 +1,118 @@
+package kal
+
+import (
+	"errors"
+	"strings"
+)
+
+// DefaultLocale is the locale that is used for the "C" and "POSIX" locales
+const DefaultLocale = "en_US"
+
+// Languages that are written with another code in the supported locales
+var languageAliases = map[string]string{
+	"no": "nb", // Norwegian
+}
+
+// Locale is a language and a region, like "nb" and "NO"
+type Locale struct {
+	Language string // lowercase ISO 639 code, like "nb"
+	Region   string // uppercase ISO 3166 code, like "NO", or empty
+}
+
+// String returns the locale in the POSIX form, like "nb_NO"
+func (l Locale) String() string {
+	if l.Region == "" {
+		return l.Language
+	}
+	return l.Language + "_" + l.Region
+}
+
+// Check if all the characters in a string are ASCII letters
+func letters(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// Check if all the characters in a string are ASCII digits
+func digits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// ParseLocale parses a POSIX locale, like "nb_NO.UTF-8" or "tr_TR@euro",
+// or a BCP 47 language tag, like "nb-NO" or "sr-Latn-RS". The character
+// set, modifier, script, variants and extensions are left out. The "C"
+// and "POSIX" locales, and the empty string, give an empty Locale.
+func ParseLocale(s string) (Locale, error) {
+	locale := strings.TrimSpace(s)
+	locale, _, _ = strings.Cut(locale, "@")
+	locale, _, _ = strings.Cut(locale, ".")
+	if locale == "" || locale == "C" || locale == "POSIX" {
+		return Locale{}, nil
+	}
+	parts := strings.FieldsFunc(locale, func(r rune) bool { return r == '_' || r == '-' })
+	if len(parts) == 0 || !letters(parts[0]) || len(parts[0]) < 2 || len(parts[0]) > 3 {
+		return Locale{}, errors.New("Invalid locale: " + s)
+	}
+	l := Locale{Language: strings.ToLower(parts[0])}
+	for _, part := range parts[1:] {
+		if len(part) == 1 {
+			// The start of an extension, like "u-ca-gregory"
+			break
+		}
+		if (len(part) == 2 && letters(part)) || (len(part) == 3 && digits(part)) {
+			l.Region = strings.ToUpper(part)
+			break
+		}
+	}
+	return l, nil
+}
+
+// MatchLocale finds the supported locale that best matches the given
+// locale, which may be in any of the forms that ParseLocale handles.
+// The language and the region are combined if there are names in the
+// language and rules for the region, like "en_NO". If not, a locale for
+// the same region is preferred, then a locale for the same language.
+// The "C" and "POSIX" locales give DefaultLocale. If there is no match,
+// the error lists the supported locales.
+func MatchLocale(s string) (string, error) {
+	l, err := ParseLocale(s)
+	if err != nil {
+		return "", errors.New(err.Error() + " (supported locales: " + strings.Join(locales, ", ") + ")")
+	}
+	if l.Language == "" {
+		return DefaultLocale, nil
+	}
+	if alias, ok := languageAliases[l.Language]; ok {
+		l.Language = alias
+	}
+	// Language and region
+	if _, ok := regions[l.Region]; ok {
+		if c, ok := FindCatalog(l.Language); ok {
+			return c.Language + "_" + l.Region, nil
+		}
+	}
+	// Region, with another language
+	if l.Region != "" {
+		for _, locale := range locales {
+			if strings.HasSuffix(locale, "_"+l.Region) {
+				return locale, nil
+			}
+		}
+	}
+	// Language, with another region
+	for _, locale := range locales {
+		if strings.HasPrefix(locale, l.Language+"_") {
+			return locale, nil
+		}
+	}
+	return "", errors.New("Locale not supported: " + s + " (supported locales: " + strings.Join(locales, ", ") + ")")
+}
@@ -0,0 +1,34 @@
+package kal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchLocale(t *testing.T) {
+	for s, want := range map[string]string{
+		"nb_NO":           "nb_NO",
+		"nb_NO.utf8":      "nb_NO",
+		"nb_NO.UTF-8":     "nb_NO",
+		"nb-NO":           "nb_NO",
+		"no_NO":           "nb_NO",
+		"no":              "nb_NO",
+		"nn_NO":           "nb_NO",
+		"en_GB":           "en_US",
+		"en-Latn-US-u-ca": "en_US",
+		"tr_TR@euro":      "tr_TR",
+		"tr":              "tr_TR",
+		"C.UTF-8":         DefaultLocale,
+		"POSIX":           DefaultLocale,
+		"":                DefaultLocale,
+	} {
+		if got, err := MatchLocale(s); err != nil || got != want {
+			t.Errorf("MatchLocale(%q) = %q, %v, want %q", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"de_DE", "x", "12_NO"} {
+		if _, err := MatchLocale(s); err == nil || !strings.Contains(err.Error(), "nb_NO") {
+			t.Errorf("MatchLocale(%q): got %v, want an error listing the supported locales", s, err)
+		}
+	}
+}
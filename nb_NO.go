@@ -7,7 +7,6 @@ package kal
 // Use this as a template for implementing other languages and locales
 
 import (
-	"strings"
 	"time"
 )
 
@@ -21,306 +20,219 @@ func NewNorwegianCalendar() NorwegianCalendar {
 // Finds the Norwegian name for a day of the week.
 // Note that time.Weekday starts at 0 with Sunday, not Monday.
 func (nc NorwegianCalendar) DayName(day time.Weekday) string {
-	return []string{"søndag", "mandag", "tirsdag", "onsdag", "torsdag", "fredag", "lørdag"}[int(day)]
+	return norwegianCatalog.DayName(day)
 }
 
 // Finds the Norwegian name for a given month
 func (nc NorwegianCalendar) MonthName(month time.Month) string {
-	return []string{"januar", "februar", "mars", "april", "mai", "juni", "juli", "august", "september", "oktober", "november", "desember"}[int(month)-1]
+	return norwegianCatalog.MonthName(month)
 }
 
-// Checks if a given date is a "red day" (public holiday) in the Norwegian calendar.
-// Returns true/false, a description and true/false for if it's a flag day.
-// The dates will never overlap.
-// Includes the 24th of December, even though only half the day is red.
-func (nc NorwegianCalendar) RedDay(date time.Time) (bool, string, bool) {
+// The red days and notable days in Norway. The names are in the catalogs.
+var norwegianRules = []rule{
+
+	// --- Red days ---
 
 	// Source: http://www.diskusjon.no/index.php?showtopic=1084239
 	// Source: http://no.wikipedia.org/wiki/Helligdager_i_Norge
 
-	var (
-		desc string
-		flag bool
-	)
-
-	// Sundays
-	if date.Weekday() == time.Sunday {
-		desc = "Søndag"
-	}
-
 	// Første nyttårsdag, 1. januar
-	if atMD(date, 1, 1) {
-		desc = "Første nyttårsdag"
-		flag = true
-	}
+	{"new_years_day", "", true, true, fixedDate(time.January, 1)},
 
 	// Palmesøndag
-	if atPalmSunday(date) {
-		desc = "Palmesøndag"
-	}
+	{"palm_sunday", "", true, false, yearly(palmSunday)},
 
 	// Skjærtorsdag (easter - 3d)
-	if atEasterPlus(date, -3) {
-		desc = "Skjærtorsdag"
-	}
+	{"maundy_thursday", "", true, false, easterPlus(-3)},
 
 	// Langfredag (easter - 2d)
-	if atEasterPlus(date, -2) {
-		desc = "Langfredag"
-	}
+	{"good_friday", "", true, false, easterPlus(-2)},
 
 	// Første påskedag
-	if atEasterPlus(date, 0) {
-		desc = "Første påskedag"
-		flag = true
-	}
+	{"easter_sunday", "", true, true, easterPlus(0)},
 
 	// Andre påskedag (easter + 1d)
-	if atEasterPlus(date, 1) {
-		desc = "Andre påskedag"
-	}
+	{"easter_monday", "", true, false, easterPlus(1)},
 
 	// Arbeidernes internasjonale kampdag, 1. mai
-	if atMD(date, 5, 1) {
-		// Arbeiderbevegelsens dag
-		desc = "Arbeidernes internasjonale kampdag"
-		flag = true
-	}
+	// (Arbeiderbevegelsens dag)
+	{"labour_day", "", true, true, fixedDate(time.May, 1)},
 
 	// Grunnlovsdagen, 17. mai
-	if atMD(date, 5, 17) {
-		// Norges grunnlovsdag/nasjonaldagen
-		desc = "Grunnlovsdagen"
-		flag = true
-	}
+	// (Norges grunnlovsdag/nasjonaldagen)
+	{"constitution_day", "", true, true, fixedDate(time.May, 17)},
 
 	// Kristi himmelfartsdag (40. påskedag: easter + 39d)
-	if atEasterPlus(date, 39) {
-		desc = "Kristi himmelfartsdag"
-	}
+	{"ascension_day", "", true, false, easterPlus(39)},
 
 	// Første pinsedag (50. påskedag: easter + 49d)
-	if atEasterPlus(date, 49) {
-		desc = "Første pinsedag"
-		flag = true
-	}
+	{"whit_sunday", "", true, true, easterPlus(49)},
 
 	// Andre pinsedag (51. påskedag: easter + 50d)
-	if atEasterPlus(date, 50) {
-		desc = "Andre pinsedag"
-	}
+	{"whit_monday", "", true, false, easterPlus(50)},
 
-	// Julaften, halv-rød dag!
-	if atMD(date, 12, 24) {
-		desc = "Julaften (halv dag)"
-	}
+	// Julaften, halv-rød dag, fra kl. 12
+	{"christmas_eve", "", true, false, fixedDate(time.December, 24)},
 
 	// Første juledag (25. desember)
-	if atMD(date, 12, 25) {
-		desc = "Første juledag"
-		flag = true
-	}
+	{"christmas_day", "", true, true, fixedDate(time.December, 25)},
 
 	// Andre juledag (26. desember)
-	if atMD(date, 12, 26) {
-		desc = "Andre juledag"
-	}
+	{"boxing_day", "", true, false, fixedDate(time.December, 26)},
 
-	// Red days
-	if desc != "" {
-		// Then return
-		return true, desc, flag
-	}
-
-	// Normal days
-	return false, desc, false
-}
-
-// Some days are not red, but special in one way or another.
-// Checks if a given date is notable. Returns true/false if the
-// given date is notable, a comma separated description (in case there are more
-// than one notable event that day) and true/false depending on if it's a flag
-// flying day or not.
-func (nc NorwegianCalendar) NotableDay(date time.Time) (bool, string, bool) {
+	// --- Notable days ---
 
 	// Source: http://www.timeanddate.no/kalender/merkedag-innhold
 	// Source: http://no.wikipedia.org/wiki/Norges_offisielle_flaggdager
 
-	var (
-		descriptions []string
-		flag         bool
-	)
-
 	// Since days may overlap, "flaggdager" must come first for the flag
 	// flying days to be correct.
 
 	// --- Flag days ---
 
 	// Frigjøringsdagen
-	if atMD(date, 5, 8) {
-		// Frigjøringsdag 1945
-		descriptions = append(descriptions, "Frigjøringsdagen")
-		flag = true
-	}
+	// (Frigjøringsdag 1945)
+	{"liberation_day", "", false, true, fixedDate(time.May, 8)},
 
 	// Samefolkets dag
-	if atMD(date, 2, 6) {
-		descriptions = append(descriptions, "Samefolkets dag")
-		flag = true
-	}
+	{"sami_national_day", "", false, true, fixedDate(time.February, 6)},
 
 	// 21 januar, H.K.H. Prinsesse Ingrid Alexandras fødselsdag
-	if atMD(date, 1, 21) {
-		descriptions = append(descriptions, "H.K.H. Prinsesse Ingrid Alexandras fødselsdag")
-		flag = true
-	}
+	{"princess_ingrid_alexandra", "", false, true, fixedDate(time.January, 21)},
 
 	// 21 februar, H.M. Kong Harald Vs fødselsdag
-	if atMD(date, 2, 21) {
-		descriptions = append(descriptions, "H.M. Kong Harald Vs fødselsdag")
-		flag = true
-	}
+	{"king_harald", "", false, true, fixedDate(time.February, 21)},
 
 	// 7 juni, unionsoppløsningen med Sverige i 1905
-	if atMD(date, 6, 7) {
-		descriptions = append(descriptions, "Unionsoppløsningen med Sverige i 1905")
-		flag = true
-	}
+	{"union_dissolution", "", false, true, fixedDate(time.June, 7)},
 
 	// 4 juli, H.M. Dronning Sonjas fødselsdag
-	if atMD(date, 7, 4) {
-		descriptions = append(descriptions, "H.M. Dronning Sonjas fødselsdag")
-		flag = true
-	}
+	{"queen_sonja", "", false, true, fixedDate(time.July, 4)},
 
 	// 20 juli, H.K.H. Kronprins Haakon Magnus' fødselsdag
-	if atMD(date, 7, 20) {
-		descriptions = append(descriptions, "H.K.H. Kronprins Haakon Magnus' fødselsdag")
-		flag = true
-	}
+	{"crown_prince_haakon", "", false, true, fixedDate(time.July, 20)},
 
 	// 29. juli, Olsokdagen
-	if atMD(date, 7, 29) {
-		descriptions = append(descriptions, "Olsokdagen")
-		flag = true
-	}
+	{"olsok", "", false, true, fixedDate(time.July, 29)},
 
 	// 19. aug, H.K.H. Kronprinsesse Mette Marits fødselsdag
-	if atMD(date, 8, 19) {
-		descriptions = append(descriptions, "H.K.H. Kronprinsesse Mette Marits fødselsdag")
-		flag = true
-	}
+	{"crown_princess_mette_marit", "", false, true, fixedDate(time.August, 19)},
 
 	// 9. sept hvert 4. år, 2013, 2017 osv, Stortingsvalg-dagen
-	if (date.Year()-1)%4 == 0 {
-		if atMD(date, 9, 9) {
-			descriptions = append(descriptions, "Stortingsvalg-dagen")
-			flag = true
-		}
-	}
+	{"parliamentary_election", "", false, true, stortingsvalg},
 
 	// --- Non-flag days ---
 
 	// Askeonsdag (fasten begynner)
-	if atEasterPlus(date, -46) {
-		descriptions = append(descriptions, "Askeonsdag")
-	}
+	{"ash_wednesday", "", false, false, easterPlus(-46)},
 
-	// Påskeaften (fasten slutter)
-	if atEasterPlus(date, -1) {
-		descriptions = append(descriptions, "Påskeaften")
-	}
+	// Påskeaften (fasten slutter), i praksis fri fra kl. 12
+	{"easter_eve", "", false, false, easterPlus(-1)},
 
 	// Fastelavnssøndag (første dag i fastelavn, festen før fasten)
 	// Source: http://www.aktivioslo.no/hvaskjer/fastelavn/
-	if atEasterPlus(date, -49) {
-		descriptions = append(descriptions, "Fastelavnsøndag")
-	}
+	{"shrove_sunday", "", false, false, easterPlus(-49)},
 
 	// Blåmandag (andre dag i fastelavn)
-	if atEasterPlus(date, -48) {
-		descriptions = append(descriptions, "Blåmandag")
-	}
+	{"shrove_monday", "", false, false, easterPlus(-48)},
 
 	// Feitetirsdag (tredje og siste dag i fastelavn, også kjent som Mardi Gras)
-	if atEasterPlus(date, -47) {
-		descriptions = append(descriptions, "Feitetirsdag (Mardi Gras)")
-	}
+	{"shrove_tuesday", "", false, false, easterPlus(-47)},
 
 	// Sankthansaften
-	if atMD(date, 6, 23) {
-		descriptions = append(descriptions, "Sankthansaften")
-	}
+	{"midsummer_eve", "", false, false, fixedDate(time.June, 23)},
 
-	// Nyttårsaften
-	if atMD(date, 12, 31) {
-		descriptions = append(descriptions, "Nyttårsaften")
-	}
+	// Nyttårsaften, i praksis fri fra kl. 12
+	{"new_years_eve", "", false, false, fixedDate(time.December, 31)},
 
 	// Morsdag
-	if atMorsdag(date) {
-		descriptions = append(descriptions, "Morsdag")
-	}
+	{"mothers_day", "", false, false, morsdag},
 
 	// Farsdag
-	if atFarsdag(date) {
-		descriptions = append(descriptions, "Farsdag")
-	}
+	{"fathers_day", "", false, false, farsdag},
 
 	// Valentinsdagen
-	if atMD(date, 2, 14) {
-		descriptions = append(descriptions, "Valentinsdagen")
-	}
+	{"valentines_day", "", false, false, fixedDate(time.February, 14)},
 
 	// Allehelgensaften (Halloween)
-	if atMD(date, 10, 31) {
-		descriptions = append(descriptions, "Allehelgensaften (Halloween)")
-	}
+	{"halloween", "", false, false, fixedDate(time.October, 31)},
 
 	// Allehelgensdag
-	if atMD(date, 11, 1) {
-		descriptions = append(descriptions, "Allehelgensdag")
-	}
+	{"all_saints_day", "", false, false, fixedDate(time.November, 1)},
 
 	// Vårjevndøgn
-	if atNorthwardEquinox(date) {
-		descriptions = append(descriptions, "Vårjevndøgn")
-	}
+	{"march_equinox", "", false, false, yearly(northwardEquinox)},
 
 	// Sommersolverv
-	if atNorthernSolstice(date) {
-		descriptions = append(descriptions, "Sommersolverv")
-	}
+	{"june_solstice", "", false, false, yearly(northernSolstice)},
 
 	// Høstjevndøgn
-	if atSouthwardEquinox(date) {
-		descriptions = append(descriptions, "Høstjevndøgn")
-	}
+	{"september_equinox", "", false, false, yearly(southwardEquinox)},
 
 	// Vintersolverv
-	if atSouthernSolstice(date) {
-		descriptions = append(descriptions, "Vintersolverv")
-	}
+	{"december_solstice", "", false, false, yearly(southernSolstice)},
 
 	// Siste søndag i mars, sommertid, klokka stilles 1 time frem
-	if atSommertid(date) {
-		descriptions = append(descriptions, "Sommertid (+1t)")
-	}
+	{"summer_time", "", false, false, yearly(sommertid)},
 
 	// Siste søndag i oktober, vintertid, klokka stilles 1 time tilbake
-	if atVintertid(date) {
-		descriptions = append(descriptions, "Vintertid (-1t)")
+	{"winter_time", "", false, false, yearly(vintertid)},
+}
+
+// The partial days off in Norway, and when the time off starts
+var norwegianHalfDays = map[string]time.Duration{
+	"christmas_eve": 12 * time.Hour, // Julaften
+	"easter_eve":    12 * time.Hour, // Påskeaften
+	"new_years_eve": 12 * time.Hour, // Nyttårsaften
+}
+
+// The red days and notable days in the Norwegian calendar, with the Norwegian names
+var norwegianDays = localRules(regions["NO"], norwegianCatalog)
+
+// 9. sept hvert 4. år, 2013, 2017 osv
+func stortingsvalg(year int) []time.Time {
+	if (year-1)%4 != 0 {
+		return nil
 	}
+	return []time.Time{time.Date(year, time.September, 9, 0, 0, 0, 0, time.UTC)}
+}
+
+// Checks if a given date is a "red day" (public holiday) in the Norwegian calendar.
+// Returns true/false, a description and true/false for if it's a flag day.
+// Weekend days are not red days, unless they are also public holidays.
+// The dates will never overlap.
+// Includes the 24th of December, even though only half the day is red, see HalfDay.
+func (nc NorwegianCalendar) RedDay(date time.Time) (bool, string, bool) {
+	return norwegianDays.redDay(date)
+}
+
+// Some days are not red, but special in one way or another.
+// Checks if a given date is notable. Returns true/false if the
+// given date is notable, a comma separated description (in case there are more
+// than one notable event that day) and true/false depending on if it's a flag
+// flying day or not.
+func (nc NorwegianCalendar) NotableDay(date time.Time) (bool, string, bool) {
+	return norwegianDays.notableDay(date)
+}
+
+// Checks if a given date is a partial day off in the Norwegian calendar, where only
+// the part of the day after a given time of day is off. Returns true/false
+// and the time of day when the time off starts, like 12 hours for 12:00.
+func (nc NorwegianCalendar) HalfDay(date time.Time) (bool, time.Duration) {
+	return norwegianDays.halfDay(date)
+}
 
-	// If there are notable events, return them as a string
-	if len(descriptions) > 0 {
-		desc := strings.Join(descriptions, ", ")
-		// Then return
-		return true, desc, flag
+// Describe what type of day a given date is
+func (nc NorwegianCalendar) describe(date time.Time, weekend bool) string {
+	if desc, ok := norwegianDays.describe(date, weekend); ok {
+		return desc
 	}
+	return nc.NormalDay()
+}
 
-	// No notable events
-	return false, "", false
+// List the red days and notable days of the given year
+func (nc NorwegianCalendar) specialDays(year int) []SpecialDay {
+	return norwegianDays.specialDays(year, true, true)
 }
 
 // Checks if a given date is in a notable time range (summer holidays, for instance)
@@ -334,11 +246,26 @@ func (nc NorwegianCalendar) NotablePeriod(date time.Time) (bool, string) {
 	return false, ""
 }
 
+// Saturday and Sunday
+func (nc NorwegianCalendar) Weekend() []time.Weekday {
+	return saturdaySunday()
+}
+
 func (nc NorwegianCalendar) MondayFirst() bool {
 	return true
 }
 
 // An ordinary day
 func (nc NorwegianCalendar) NormalDay() string {
-	return "Hverdag"
+	return norwegianCatalog.NormalDay
+}
+
+// The Norwegian words for the parts of a period
+func (nc NorwegianCalendar) periodWords() periodWords {
+	return norwegianCatalog.periodWords()
+}
+
+// The Norwegian names
+func (nc NorwegianCalendar) catalog() *Catalog {
+	return norwegianCatalog
 }
@@ -0,0 +1,79 @@
+package kal
+
+// Finding the days and hours when people in several locales are all working
+
+import (
+	"time"
+)
+
+// A DayOff says why one of several calendars does not have a business day
+type DayOff struct {
+	Index       int // the index of the calendar, in the order the calendars were given
+	Description string
+}
+
+// Availability is a date, and the calendars that have a day off on that date
+type Availability struct {
+	Date time.Time
+	Off  []DayOff // empty if the date is a business day in all the calendars
+}
+
+// Common checks if the date is a business day in all the calendars
+func (a Availability) Common() bool {
+	return len(a.Off) == 0
+}
+
+// CalendarAvailability returns the availability for every date from and
+// including the date of from, to and including the date of to. For each
+// calendar that does not have a business day, the day is described.
+func CalendarAvailability(from, to time.Time, cals ...Calendar) []Availability {
+	var days []Availability
+	current, last := dateSpan(from, to, time.UTC)
+	for ; !current.After(last); current = current.AddDate(0, 0, 1) {
+		a := Availability{Date: current}
+		for i, cal := range cals {
+			if !BusinessDay(cal, current) {
+				a.Off = append(a.Off, DayOff{i, Describe(cal, current)})
+			}
+		}
+		days = append(days, a)
+	}
+	return days
+}
+
+// CommonBusinessDays returns the dates that are business days in all the
+// given calendars, from and including the date of from, to and including
+// the date of to
+func CommonBusinessDays(from, to time.Time, cals ...Calendar) []time.Time {
+	var dates []time.Time
+	for _, a := range CalendarAvailability(from, to, cals...) {
+		if a.Common() {
+			dates = append(dates, a.Date)
+		}
+	}
+	return dates
+}
+
+// OverlappingHours finds the time when all the given business hours are
+// open on the given date, where the date is the same calendar date in
+// each time zone. Returns false if there is no overlap.
+func OverlappingHours(date time.Time, bhs ...BusinessHours) (time.Time, time.Time, bool) {
+	var start, end time.Time
+	year, month, day := date.Date()
+	for i, bh := range bhs {
+		open, close, ok := bh.hours(time.Date(year, month, day, 0, 0, 0, 0, bh.Location), 0)
+		if !ok {
+			return time.Time{}, time.Time{}, false
+		}
+		if i == 0 || open.After(start) {
+			start = open
+		}
+		if i == 0 || close.Before(end) {
+			end = close
+		}
+	}
+	if len(bhs) == 0 || !end.After(start) {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
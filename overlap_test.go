@@ -0,0 +1,32 @@
+package kal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCommonBusinessDays(t *testing.T) {
+	no, us, tr := NewNorwegianCalendar(), NewUSCalendar(), NewTRCalendar()
+	days := CommonBusinessDays(date(2025, time.December, 22), date(2025, time.December, 31), no, us, tr)
+	// 25 and 26 December are days off in Norway, and the weekend is a day off
+	// everywhere. Julaften is a half day, and still a business day.
+	if len(days) != 6 {
+		t.Errorf("got %v, want 6 days", days)
+	}
+	a := CalendarAvailability(date(2025, time.December, 25), date(2025, time.December, 25), no, us, tr)
+	if len(a) != 1 || len(a[0].Off) != 2 || a[0].Off[1] != (DayOff{1, "Christmas Day"}) {
+		t.Errorf("got %+v", a)
+	}
+
+	oslo, err1 := time.LoadLocation("Europe/Oslo")
+	newYork, err2 := time.LoadLocation("America/New_York")
+	if err1 != nil || err2 != nil {
+		t.Skip("no time zone database")
+	}
+	start, end, ok := OverlappingHours(date(2025, time.December, 22),
+		NewBusinessHours(no, WorkWeek(8*time.Hour, 16*time.Hour), oslo),
+		NewBusinessHours(us, WorkWeek(9*time.Hour, 17*time.Hour), newYork))
+	if !ok || start.UTC().Hour() != 14 || end.UTC().Hour() != 15 {
+		t.Errorf("OverlappingHours: got %s - %s, %v", start.UTC(), end.UTC(), ok)
+	}
+}
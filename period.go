@@ -0,0 +1,132 @@
+package kal
+
+// Calendar-aware differences between dates, like "2 years, 3 months and 4 days"
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Period is a difference between two dates in years, months and days.
+// For a negative period, all the fields are zero or negative.
+type Period struct {
+	Years  int
+	Months int
+	Days   int
+}
+
+// Diff returns the period from a to b, ignoring the time of day.
+// Whole months are counted first, with AddMonths, then the remaining days.
+// Adding a month to January 31st gives the last day of February, and a
+// person born on February 29th is one year older on February 28th in the
+// years that are not leap years. If b is before a, the period is negative.
+func Diff(a, b time.Time) Period {
+	y1, m1, d1 := a.Date()
+	y2, m2, d2 := b.Date()
+	a = time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)
+	b = time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC)
+	if b.Before(a) {
+		return Diff(b, a).Negate()
+	}
+	months := (y2-y1)*12 + int(m2) - int(m1)
+	for months > 0 && AddMonths(a, months, false).After(b) {
+		months--
+	}
+	return Period{
+		Years:  months / 12,
+		Months: months % 12,
+		Days:   actualDays(AddMonths(a, months, false), b),
+	}
+}
+
+// Negate returns the period with the opposite sign
+func (p Period) Negate() Period {
+	return Period{-p.Years, -p.Months, -p.Days}
+}
+
+// IsZero checks if the period is zero
+func (p Period) IsZero() bool {
+	return p.Years == 0 && p.Months == 0 && p.Days == 0
+}
+
+// Add adds two periods, field by field, and normalizes the result
+func (p Period) Add(q Period) Period {
+	return Period{p.Years + q.Years, p.Months + q.Months, p.Days + q.Days}.Normalize()
+}
+
+// Normalize moves whole years from the months to the years, so that the
+// months are from -11 to 11 and have the same sign as the years.
+// The days are left as they are, since the length of a month depends on
+// the date the period is added to.
+func (p Period) Normalize() Period {
+	months := p.Years*12 + p.Months
+	return Period{months / 12, months % 12, p.Days}
+}
+
+// AddTo adds the period to a date. The years and months are added first,
+// with AddMonths, then the days.
+func (p Period) AddTo(date time.Time) time.Time {
+	return AddMonths(date, p.Years*12+p.Months, false).AddDate(0, 0, p.Days)
+}
+
+// The words that are used when formatting a period in a language
+type periodWords struct {
+	year, years, month, months, day, days, and string
+}
+
+// periodWorder is implemented by calendars that can name the parts of a period
+type periodWorder interface {
+	periodWords() periodWords
+}
+
+// The English words, for calendars that don't have their own
+var englishPeriodWords = periodWords{"year", "years", "month", "months", "day", "days", "and"}
+
+// Find the words for formatting a period with the given calendar
+func calendarPeriodWords(cal Calendar) periodWords {
+	if pw, ok := cal.(periodWorder); ok {
+		return pw.periodWords()
+	}
+	return englishPeriodWords
+}
+
+// Format the period in the language of the given calendar, like
+// "2 years, 3 months and 4 days". Zero fields are left out, and the years
+// and months are normalized first. A negative period is formatted with a
+// single leading minus, like "-1 month". If the days have the opposite
+// sign of the years and months, every field is signed, like "+1 year and -3 days".
+func (p Period) Format(cal Calendar) string {
+	words := calendarPeriodWords(cal)
+	p = p.Normalize()
+	sign := ""
+	if p.Years <= 0 && p.Months <= 0 && p.Days <= 0 && !p.IsZero() {
+		sign = "-"
+		p = p.Negate()
+	}
+	mixed := p.Years < 0 || p.Months < 0 || p.Days < 0
+	part := func(n int, singular, plural string) string {
+		s := strconv.Itoa(n)
+		if mixed && n > 0 {
+			s = "+" + s
+		}
+		if n == 1 || n == -1 {
+			return s + " " + singular
+		}
+		return s + " " + plural
+	}
+	var parts []string
+	if p.Years != 0 {
+		parts = append(parts, part(p.Years, words.year, words.years))
+	}
+	if p.Months != 0 {
+		parts = append(parts, part(p.Months, words.month, words.months))
+	}
+	if p.Days != 0 || len(parts) == 0 {
+		parts = append(parts, part(p.Days, words.day, words.days))
+	}
+	if len(parts) == 1 {
+		return sign + parts[0]
+	}
+	return sign + strings.Join(parts[:len(parts)-1], ", ") + " " + words.and + " " + parts[len(parts)-1]
+}
@@ -0,0 +1,49 @@
+package kal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	for _, tc := range []struct {
+		a, b time.Time
+		want Period
+	}{
+		{date(2020, time.January, 15), date(2022, time.April, 19), Period{2, 3, 4}},
+		{date(2025, time.January, 31), date(2025, time.February, 28), Period{0, 1, 0}},
+		{date(2025, time.January, 31), date(2025, time.March, 1), Period{0, 1, 1}},
+		{date(2020, time.February, 29), date(2021, time.February, 28), Period{1, 0, 0}},
+		{date(2020, time.February, 29), date(2024, time.February, 29), Period{4, 0, 0}},
+		{date(2022, time.April, 19), date(2020, time.January, 15), Period{-2, -3, -4}},
+	} {
+		got := Diff(tc.a, tc.b)
+		if got != tc.want {
+			t.Errorf("Diff(%s, %s): got %+v, want %+v", tc.a, tc.b, got, tc.want)
+		}
+		if back := got.AddTo(tc.a); got.Years >= 0 && !back.Equal(tc.b) {
+			t.Errorf("AddTo: got %s, want %s", back, tc.b)
+		}
+	}
+	if got := (Period{1, 11, 3}).Add(Period{0, 2, 1}); got != (Period{2, 1, 4}) {
+		t.Errorf("Add: got %+v", got)
+	}
+	if got := (Period{2, 3, 1}).Format(NewNorwegianCalendar()); got != "2 år, 3 måneder og 1 dag" {
+		t.Errorf("Format: got %q", got)
+	}
+	if got := (Period{0, -1, 0}).Format(NewCachedCalendar(NewUSCalendar())); got != "-1 month" {
+		t.Errorf("Format: got %q", got)
+	}
+	for p, want := range map[Period]string{
+		{1, 0, -3}:  "+1 year and -3 days",
+		{-1, 0, 3}:  "-1 year and +3 days",
+		{-1, 2, 0}:  "-10 months",
+		{0, 13, 0}:  "1 year and 1 month",
+		{-2, 0, -1}: "-2 years and 1 day",
+		{0, 0, 0}:   "0 days",
+	} {
+		if got := p.Format(NewUSCalendar()); got != want {
+			t.Errorf("Format(%+v): got %q, want %q", p, got, want)
+		}
+	}
+}
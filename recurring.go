@@ -0,0 +1,158 @@
+package kal
+
+// Recurring schedules, like garbage collection or payroll runs, that shift around holidays
+
+import (
+	"strings"
+	"time"
+)
+
+// A Frequency is how often a recurring schedule or a recurrence rule repeats
+type Frequency int
+
+const (
+	// Weekly repeats every week, on the same day of the week as the start
+	Weekly Frequency = iota
+	// Monthly repeats every month, on the same day of the month as the start,
+	// or on the last day of the month if the month is shorter
+	Monthly
+	// Daily repeats every day
+	Daily
+	// Yearly repeats every year, on the same date as the start, or on
+	// February 28th for February 29th in years that are not leap years
+	Yearly
+)
+
+// A ShiftPolicy says how an occurrence of a recurring schedule is moved
+// when it falls on a day off
+type ShiftPolicy int
+
+const (
+	// NoShift keeps the occurrences where they are
+	NoShift ShiftPolicy = iota
+	// NextBusinessDay moves an occurrence on a day off to the next business day
+	NextBusinessDay
+	// PreviousBusinessDay moves an occurrence on a day off to the previous business day
+	PreviousBusinessDay
+	// SlideWeek moves an occurrence one day later for every public holiday
+	// from the Monday of the same week up to and including the occurrence,
+	// like garbage collection that slides after a holiday. If the new day is
+	// a holiday too, it moves on to the next day. Weekends are not skipped,
+	// so a Friday pickup may slide to Saturday.
+	SlideWeek
+	// Skip leaves out the occurrences that fall on a day off
+	Skip
+)
+
+// String returns the name of the shift policy
+func (sp ShiftPolicy) String() string {
+	switch sp {
+	case NoShift:
+		return "No shift"
+	case NextBusinessDay:
+		return "Next business day"
+	case PreviousBusinessDay:
+		return "Previous business day"
+	case SlideWeek:
+		return "Slide week"
+	case Skip:
+		return "Skip"
+	}
+	return "Unknown"
+}
+
+// A RecurringSchedule is a daily, weekly, monthly or yearly pattern, that
+// starts at the given time and that is shifted around the days off in the
+// calendar
+type RecurringSchedule struct {
+	Calendar  Calendar
+	Start     time.Time // the first occurrence, also giving the time of day
+	Frequency Frequency
+	Interval  int // repeat every Interval days, weeks, months or years, 0 is the same as 1
+	Policy    ShiftPolicy
+}
+
+// An Occurrence is a single occurrence of a recurring schedule
+type Occurrence struct {
+	Original time.Time // the date from the pattern
+	Date     time.Time // the date after shifting, the same as Original if not moved
+	Moved    bool
+	Skipped  bool   // true if the occurrence was left out by the Skip policy
+	Reason   string // the days off that made the occurrence move, or be skipped
+}
+
+// Find the nth date of the base pattern, counting from 0
+func (rs RecurringSchedule) patternDate(n int) time.Time {
+	interval := rs.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	switch rs.Frequency {
+	case Monthly:
+		return AddMonths(rs.Start, n*interval, false)
+	case Daily:
+		return rs.Start.AddDate(0, 0, n*interval)
+	case Yearly:
+		return AddMonths(rs.Start, 12*n*interval, false)
+	}
+	return rs.Start.AddDate(0, 0, 7*n*interval)
+}
+
+// Shift a single date of the pattern according to the policy
+func (rs RecurringSchedule) shift(date time.Time) Occurrence {
+	o := Occurrence{Original: date, Date: date}
+	cal := rs.Calendar
+	switch rs.Policy {
+	case NextBusinessDay, PreviousBusinessDay, Skip:
+		if BusinessDay(cal, date) {
+			return o
+		}
+		o.Reason = Describe(cal, date)
+		switch rs.Policy {
+		case NextBusinessDay:
+			o.Date = Adjust(cal, date, Following)
+		case PreviousBusinessDay:
+			o.Date = Adjust(cal, date, Preceding)
+		default:
+			o.Skipped = true
+		}
+	case SlideWeek:
+		// Count the holidays from Monday up to and including the date
+		var reasons []string
+		monday := date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7))
+		for d := monday; !d.After(date); d = d.AddDate(0, 0, 1) {
+			if Holiday(cal, d) && !WeekendDay(cal, d) {
+				reasons = append(reasons, Describe(cal, d))
+				o.Date = o.Date.AddDate(0, 0, 1)
+			}
+		}
+		for i := 0; i < 7 && len(reasons) > 0 && Holiday(cal, o.Date); i++ {
+			reasons = append(reasons, Describe(cal, o.Date))
+			o.Date = o.Date.AddDate(0, 0, 1)
+		}
+		o.Reason = strings.Join(reasons, ", ")
+	}
+	o.Moved = !o.Date.Equal(date) && !o.Skipped
+	return o
+}
+
+// Occurrences returns the occurrences of the schedule where the date from
+// the pattern is from and including the date of from, to and including the
+// date of to. The occurrences that were skipped are included too, with
+// Skipped set to true.
+func (rs RecurringSchedule) Occurrences(from, to time.Time) []Occurrence {
+	var occurrences []Occurrence
+	first, last := dateSpan(from, to, rs.Start.Location())
+	last = last.AddDate(0, 0, 1)
+	for n := 0; ; n++ {
+		date := rs.patternDate(n)
+		if !date.Before(last) {
+			break
+		}
+		if date.Before(first) {
+			continue
+		}
+		occurrences = append(occurrences, rs.shift(date))
+	}
+	return occurrences
+}
@@ -0,0 +1,44 @@
+package kal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecurringSchedule(t *testing.T) {
+	cal := NewNorwegianCalendar()
+
+	// Garbage collection every Wednesday, that slides a day after Easter Monday and Ascension Day
+	rs := RecurringSchedule{Calendar: cal, Start: date(2025, time.January, 1), Frequency: Weekly, Policy: SlideWeek}
+	occurrences := rs.Occurrences(date(2025, time.April, 20), date(2025, time.May, 31))
+	if len(occurrences) != 6 {
+		t.Fatalf("got %d occurrences, want 6", len(occurrences))
+	}
+	if o := occurrences[0]; !o.Moved || !o.Date.Equal(date(2025, time.April, 24)) || o.Reason != "Andre påskedag" {
+		t.Errorf("got %+v", o)
+	}
+	if o := occurrences[1]; o.Moved || o.Reason != "" {
+		t.Errorf("got %+v", o)
+	}
+
+	// A Friday pickup slides to Saturday after Ascension Day
+	rs.Start = date(2025, time.January, 3)
+	if o := rs.Occurrences(date(2025, time.May, 30), date(2025, time.May, 30)); len(o) != 1 || !o[0].Date.Equal(date(2025, time.May, 31)) {
+		t.Errorf("got %+v", o)
+	}
+
+	// Payroll on the 20th, moved to the previous business day
+	rs = RecurringSchedule{Calendar: cal, Start: date(2025, time.January, 20), Frequency: Monthly, Policy: PreviousBusinessDay}
+	occurrences = rs.Occurrences(date(2025, time.January, 1), date(2025, time.December, 31))
+	if len(occurrences) != 12 {
+		t.Fatalf("got %d occurrences, want 12", len(occurrences))
+	}
+	if o := occurrences[11]; !o.Moved || !o.Date.Equal(date(2025, time.December, 19)) || o.Reason != "Lørdag" {
+		t.Errorf("got %+v", o)
+	}
+
+	rs.Policy = Skip
+	if o := rs.Occurrences(date(2025, time.December, 1), date(2025, time.December, 31)); len(o) != 1 || !o[0].Skipped || o[0].Moved {
+		t.Errorf("got %+v", o)
+	}
+}
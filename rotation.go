@@ -0,0 +1,212 @@
+package kal
+
+// Shift rotations (turnus) for teams, with holiday premium hours
+
+import (
+	"errors"
+	"time"
+)
+
+// A Shift is a kind of shift, given as the time of day when it starts and
+// ends. If End is not after Start, the shift ends the next day.
+// A Shift with an empty Name is a day off.
+type Shift struct {
+	Name  string
+	Start time.Duration
+	End   time.Duration
+}
+
+// Off checks if the shift is a day off
+func (s Shift) Off() bool {
+	return s.Name == ""
+}
+
+// Duration returns the length of the shift
+func (s Shift) Duration() time.Duration {
+	if s.Off() {
+		return 0
+	}
+	if s.End <= s.Start {
+		return s.End + 24*time.Hour - s.Start
+	}
+	return s.End - s.Start
+}
+
+// ParseShiftPattern parses a pattern with one letter per day, where each
+// letter is the first letter of the name of one of the given shifts, and
+// where "-" or "." is a day off. For example, with a day shift named "D"
+// and a night shift named "N", the 2-2-3 rotation for four teams is
+// "DD--DDD--DD---NN--NNN--NN---".
+func ParseShiftPattern(pattern string, shifts ...Shift) ([]Shift, error) {
+	var days []Shift
+	for _, r := range pattern {
+		if r == '-' || r == '.' {
+			days = append(days, Shift{})
+			continue
+		}
+		found := false
+		for _, shift := range shifts {
+			if !shift.Off() && []rune(shift.Name)[0] == r {
+				days = append(days, shift)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.New("Unknown shift in pattern: " + string(r))
+		}
+	}
+	if len(days) == 0 {
+		return nil, errors.New("The shift pattern is empty")
+	}
+	return days, nil
+}
+
+// A Team follows the shift pattern of a rotation, starting Offset days
+// into the pattern later than a team with offset 0
+type Team struct {
+	Name   string
+	Offset int
+}
+
+// A Rotation is a repeating shift pattern for a number of teams. The
+// premiums are paid for the hours on public holidays, and for the hours
+// after EveStart on half days, like julaften.
+type Rotation struct {
+	Calendar Calendar
+	Start    time.Time // the first day of the pattern, for a team with offset 0
+	Pattern  []Shift   // the shift for each day in the pattern
+	Teams    []Team
+	EveStart time.Duration // the time of day when the premium starts on half days, 0 uses the start of the half day
+}
+
+// Create a new rotation where the given number of teams, named A, B, C
+// and so on, are spread out evenly over the pattern
+func NewRotation(cal Calendar, start time.Time, pattern []Shift, teams int) Rotation {
+	r := Rotation{Calendar: cal, Start: start, Pattern: pattern}
+	for i := 0; i < teams; i++ {
+		r.Teams = append(r.Teams, Team{string(rune('A' + i)), i * len(pattern) / teams})
+	}
+	return r
+}
+
+// A ShiftAssignment is a shift for a team on a given date
+type ShiftAssignment struct {
+	Team         string
+	Shift        Shift
+	Start        time.Time
+	End          time.Time
+	Holiday      bool          // part of the shift is on a public holiday
+	Eve          bool          // part of the shift is on a half day, after the premium starts
+	PremiumHours time.Duration // the part of the shift that is on a holiday or an eve
+}
+
+// The start of the premium on the given date, and true if there is a premium.
+// A premium on a public holiday starts at 00:00, also if the calendar
+// marks the day as a half day, since the whole day is off.
+func (r Rotation) premiumStart(date time.Time) (time.Duration, bool, bool) {
+	if Holiday(r.Calendar, date) {
+		return 0, true, false
+	}
+	if half, start := HalfDay(r.Calendar, date); half {
+		if r.EveStart > 0 {
+			start = r.EveStart
+		}
+		return start, false, true
+	}
+	return 0, false, false
+}
+
+// OnDuty returns the shifts that start on the given date, in the order of the teams
+func (r Rotation) OnDuty(date time.Time) []ShiftAssignment {
+	var assignments []ShiftAssignment
+	if len(r.Pattern) == 0 {
+		return assignments
+	}
+	loc := r.Start.Location()
+	year, month, day := date.Date()
+	y, m, d := r.Start.Date()
+	days := actualDays(time.Date(y, m, d, 0, 0, 0, 0, time.UTC), time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+	for _, team := range r.Teams {
+		i := ((days-team.Offset)%len(r.Pattern) + len(r.Pattern)) % len(r.Pattern)
+		shift := r.Pattern[i]
+		if shift.Off() {
+			continue
+		}
+		a := ShiftAssignment{
+			Team:  team.Name,
+			Shift: shift,
+			Start: atTimeOfDay(year, month, day, shift.Start, loc),
+		}
+		a.End = atTimeOfDay(year, month, day, shift.Start+shift.Duration(), loc)
+		// Find the premium hours for each day the shift touches
+		for i := 0; i < 2; i++ {
+			start, holiday, eve := r.premiumStart(time.Date(year, month, day+i, 0, 0, 0, 0, loc))
+			if !holiday && !eve {
+				continue
+			}
+			from := atTimeOfDay(year, month, day+i, start, loc)
+			to := time.Date(year, month, day+i+1, 0, 0, 0, 0, loc)
+			if from.Before(a.Start) {
+				from = a.Start
+			}
+			if to.After(a.End) {
+				to = a.End
+			}
+			if to.After(from) {
+				a.PremiumHours += to.Sub(from)
+				a.Holiday = a.Holiday || holiday
+				a.Eve = a.Eve || eve
+			}
+		}
+		assignments = append(assignments, a)
+	}
+	return assignments
+}
+
+// Assignments returns the shifts that start from and including the date of
+// from, to and including the date of to
+func (r Rotation) Assignments(from, to time.Time) []ShiftAssignment {
+	var assignments []ShiftAssignment
+	current, last := dateSpan(from, to, time.UTC)
+	for ; !current.After(last); current = current.AddDate(0, 0, 1) {
+		assignments = append(assignments, r.OnDuty(current)...)
+	}
+	return assignments
+}
+
+// A PremiumSummary is the number of shifts and hours for a team in a period
+type PremiumSummary struct {
+	Team          string
+	Shifts        int
+	HolidayShifts int // shifts with hours on public holidays
+	EveShifts     int // shifts with premium hours on half days
+	Hours         time.Duration
+	PremiumHours  time.Duration
+}
+
+// SummarizePremiums sums up the shifts and premium hours for each team,
+// in the order the teams first appear in the given assignments
+func SummarizePremiums(assignments []ShiftAssignment) []PremiumSummary {
+	var summaries []PremiumSummary
+	index := make(map[string]int)
+	for _, a := range assignments {
+		i, ok := index[a.Team]
+		if !ok {
+			i = len(summaries)
+			index[a.Team] = i
+			summaries = append(summaries, PremiumSummary{Team: a.Team})
+		}
+		s := &summaries[i]
+		s.Shifts++
+		if a.Holiday {
+			s.HolidayShifts++
+		}
+		if a.Eve {
+			s.EveShifts++
+		}
+		s.Hours += a.End.Sub(a.Start)
+		s.PremiumHours += a.PremiumHours
+	}
+	return summaries
+}
@@ -0,0 +1,57 @@
+package kal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRotation(t *testing.T) {
+	day := Shift{"D", 7 * time.Hour, 19 * time.Hour}
+	night := Shift{"N", 19 * time.Hour, 7 * time.Hour}
+	pattern, err := ParseShiftPattern("DD--DDD--DD---NN--NNN--NN---", day, night)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRotation(NewNorwegianCalendar(), date(2024, time.January, 1), pattern, 4)
+	r.EveStart = 15 * time.Hour
+
+	// Every day has one team on the day shift and one team on the night shift
+	for d := date(2025, time.January, 1); d.Year() == 2025; d = d.AddDate(0, 0, 1) {
+		if onDuty := r.OnDuty(d); len(onDuty) != 2 || onDuty[0].Shift.Name == onDuty[1].Shift.Name {
+			t.Fatalf("%s: got %+v", d, onDuty)
+		}
+	}
+
+	// The night shift from nyttårsaften to the first day of the new year has
+	// 5 premium hours on the eve and 7 hours on the public holiday
+	for _, a := range r.OnDuty(date(2025, time.December, 31)) {
+		want := 4 * time.Hour
+		if a.Shift.Name == "N" {
+			want = 12 * time.Hour
+		}
+		if !a.Eve || a.PremiumHours != want {
+			t.Errorf("%+v: got %s premium hours, want %s", a, a.PremiumHours, want)
+		}
+	}
+
+	// Julaften is a public holiday, even if it is also a half day, so the
+	// premium starts at 00:00 and both shifts have premium for all hours
+	for _, a := range r.OnDuty(date(2025, time.December, 24)) {
+		if a.Eve || !a.Holiday || a.PremiumHours != 12*time.Hour {
+			t.Errorf("%+v: got %s premium hours, want 12h", a, a.PremiumHours)
+		}
+	}
+
+	var total time.Duration
+	for _, s := range SummarizePremiums(r.Assignments(date(2025, time.December, 1), date(2025, time.December, 31))) {
+		total += s.PremiumHours
+	}
+	// 24, 25 and 26 December are public holidays, and the eve on the 31st
+	// has 9 hours after 15:00 plus 7 hours on the next day
+	if want := 3*24*time.Hour + 16*time.Hour; total != want {
+		t.Errorf("got %s premium hours in total, want %s", total, want)
+	}
+	if _, err := ParseShiftPattern("DX", day); err == nil {
+		t.Error("expected an error for an unknown shift")
+	}
+}
@@ -0,0 +1,484 @@
+package kal
+
+// Recurrence rules from RFC 5545 (iCalendar), for custom recurring events
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A WeekdayNum is a day of the week in a BYDAY rule part, with an optional
+// ordinal, like 1TU for the first Tuesday or -1FR for the last Friday.
+// N is 0 for every such day of the week.
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// An RRule is a recurrence rule, as described in RFC 5545, section 3.3.10.
+// FREQ=DAILY, WEEKLY, MONTHLY and YEARLY are supported, together with
+// INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH and BYSETPOS.
+// Weeks start on Monday. The time of day is the time of day of DTSTART.
+type RRule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int       // the maximum number of occurrences, 0 for no limit
+	Until      time.Time // the last possible occurrence, the zero time for no limit
+	ByDay      []WeekdayNum
+	ByMonthDay []int // days of the month, where -1 is the last day
+	ByMonth    []time.Month
+	BySetPos   []int // positions in the occurrences of each period, where -1 is the last one
+	Dtstart    time.Time
+}
+
+// The two-letter day names in RFC 5545, indexed by time.Weekday
+var rfc5545Days = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Parse a comma separated list of integers, that are from min to max and not 0
+func parseIntList(value string, min, max int) ([]int, error) {
+	var ints []int
+	for _, s := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(s)
+		if err != nil || n == 0 || n < min || n > max {
+			return nil, errors.New("Invalid number in recurrence rule: " + s)
+		}
+		ints = append(ints, n)
+	}
+	return ints, nil
+}
+
+// Parse a date or a date and time value, like 20250101, 20250101T090000 or
+// 20250101T090000Z, in the given time zone unless it is given in UTC.
+// Returns true if the value is a date without a time of day.
+func parseICSTime(value string, loc *time.Location) (time.Time, bool, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// ParseRRule parses a recurrence rule like "FREQ=WEEKLY;INTERVAL=2;BYDAY=WE",
+// with or without the "RRULE:" prefix, for the given start time. Rule parts
+// that are not supported give an error.
+func ParseRRule(rule string, dtstart time.Time) (RRule, error) {
+	r := RRule{Interval: 1, Dtstart: dtstart}
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	hasFreq := false
+	for _, part := range strings.Split(rule, ";") {
+		nameValue := strings.SplitN(part, "=", 2)
+		if len(nameValue) != 2 {
+			return r, errors.New("Invalid recurrence rule part: " + part)
+		}
+		name, value := strings.ToUpper(nameValue[0]), nameValue[1]
+		var err error
+		switch name {
+		case "FREQ":
+			hasFreq = true
+			switch strings.ToUpper(value) {
+			case "DAILY":
+				r.Freq = Daily
+			case "WEEKLY":
+				r.Freq = Weekly
+			case "MONTHLY":
+				r.Freq = Monthly
+			case "YEARLY":
+				r.Freq = Yearly
+			default:
+				return r, errors.New("Unsupported frequency: " + value)
+			}
+		case "INTERVAL":
+			if r.Interval, err = strconv.Atoi(value); err != nil || r.Interval < 1 {
+				return r, errors.New("Invalid interval: " + value)
+			}
+		case "COUNT":
+			if r.Count, err = strconv.Atoi(value); err != nil || r.Count < 1 {
+				return r, errors.New("Invalid count: " + value)
+			}
+		case "UNTIL":
+			if r.Until, _, err = parseICSTime(value, dtstart.Location()); err != nil {
+				return r, errors.New("Invalid until: " + value)
+			}
+			// An UNTIL date includes the whole day
+			if len(value) == 8 {
+				r.Until = r.Until.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			}
+		case "BYDAY":
+			for _, s := range strings.Split(strings.ToUpper(value), ",") {
+				if len(s) < 2 {
+					return r, errors.New("Invalid day: " + s)
+				}
+				wn := WeekdayNum{Weekday: -1}
+				for i, day := range rfc5545Days {
+					if strings.HasSuffix(s, day) {
+						wn.Weekday = time.Weekday(i)
+					}
+				}
+				if wn.Weekday < 0 {
+					return r, errors.New("Invalid day: " + s)
+				}
+				if prefix := strings.TrimPrefix(s[:len(s)-2], "+"); prefix != "" {
+					if wn.N, err = strconv.Atoi(prefix); err != nil || wn.N == 0 || wn.N < -53 || wn.N > 53 {
+						return r, errors.New("Invalid day: " + s)
+					}
+				}
+				r.ByDay = append(r.ByDay, wn)
+			}
+		case "BYMONTHDAY":
+			if r.ByMonthDay, err = parseIntList(value, -31, 31); err != nil {
+				return r, err
+			}
+		case "BYMONTH":
+			months, err := parseIntList(value, 1, 12)
+			if err != nil {
+				return r, err
+			}
+			for _, m := range months {
+				r.ByMonth = append(r.ByMonth, time.Month(m))
+			}
+		case "BYSETPOS":
+			if r.BySetPos, err = parseIntList(value, -366, 366); err != nil {
+				return r, err
+			}
+		case "WKST":
+			if strings.ToUpper(value) != "MO" {
+				return r, errors.New("Unsupported week start: " + value)
+			}
+		default:
+			return r, errors.New("Unsupported recurrence rule part: " + name)
+		}
+	}
+	if !hasFreq {
+		return r, errors.New("The recurrence rule has no frequency: " + rule)
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return r, errors.New("The recurrence rule can not have both COUNT and UNTIL")
+	}
+	// Ordinals, like 2MO, are only for monthly and yearly rules
+	if r.Freq == Daily || r.Freq == Weekly {
+		for _, wn := range r.ByDay {
+			if wn.N != 0 {
+				return r, errors.New("A daily or weekly recurrence rule can not have an ordinal day: " + rule)
+			}
+		}
+	}
+	return r, nil
+}
+
+// Checks if the given date matches one of the days of the month, where
+// negative days count from the end of the month
+func matchMonthDay(date time.Time, days []int) bool {
+	last := daysInMonth(date.Year(), date.Month())
+	for _, day := range days {
+		if day == date.Day() || (day < 0 && last+day+1 == date.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks if the given date matches one of the weekdays, where the ordinals
+// are counted within the month, or within the year if inYear is true
+func matchWeekday(date time.Time, days []WeekdayNum, inYear bool) bool {
+	for _, wn := range days {
+		if date.Weekday() != wn.Weekday {
+			continue
+		}
+		if wn.N == 0 {
+			return true
+		}
+		// Count the same day of the week from the start and from the end
+		var first, last time.Time
+		if inYear {
+			first = time.Date(date.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+			last = time.Date(date.Year(), time.December, 31, 0, 0, 0, 0, time.UTC)
+		} else {
+			first = time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+			last = time.Date(date.Year(), date.Month(), daysInMonth(date.Year(), date.Month()), 0, 0, 0, 0, time.UTC)
+		}
+		d := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+		if (wn.N > 0 && actualDays(first, d)/7+1 == wn.N) || (wn.N < 0 && -(actualDays(d, last)/7+1) == wn.N) {
+			return true
+		}
+	}
+	return false
+}
+
+// The first day of the nth period, counting from the period of Dtstart
+func (r RRule) periodStart(n int) time.Time {
+	year, month, day := r.Dtstart.Date()
+	switch r.Freq {
+	case Yearly:
+		return time.Date(year+n*r.Interval, time.January, 1, 0, 0, 0, 0, time.UTC)
+	case Monthly:
+		return time.Date(year, month+time.Month(n*r.Interval), 1, 0, 0, 0, 0, time.UTC)
+	case Weekly:
+		monday := day - (int(r.Dtstart.Weekday())+6)%7
+		return time.Date(year, month, monday+7*n*r.Interval, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(year, month, day+n*r.Interval, 0, 0, 0, 0, time.UTC)
+}
+
+// The occurrences in the nth period, in order, before COUNT and UNTIL are applied
+func (r RRule) period(n int) []time.Time {
+	start := r.periodStart(n)
+	var end time.Time
+	switch r.Freq {
+	case Yearly:
+		end = start.AddDate(1, 0, 0)
+	case Monthly:
+		end = start.AddDate(0, 1, 0)
+	case Weekly:
+		end = start.AddDate(0, 0, 7)
+	default:
+		end = start.AddDate(0, 0, 1)
+	}
+
+	// Use the date of DTSTART when the rule does not say which days to use
+	byMonth, byMonthDay, byDay := r.ByMonth, r.ByMonthDay, r.ByDay
+	switch r.Freq {
+	case Yearly:
+		if len(byMonth) == 0 && len(byMonthDay) == 0 && len(byDay) == 0 {
+			byMonth = []time.Month{r.Dtstart.Month()}
+		}
+		if len(byMonthDay) == 0 && len(byDay) == 0 {
+			byMonthDay = []int{r.Dtstart.Day()}
+		}
+	case Monthly:
+		if len(byMonthDay) == 0 && len(byDay) == 0 {
+			byMonthDay = []int{r.Dtstart.Day()}
+		}
+	case Weekly:
+		if len(byDay) == 0 {
+			byDay = []WeekdayNum{{0, r.Dtstart.Weekday()}}
+		}
+	}
+	inYear := r.Freq == Yearly && len(r.ByMonth) == 0
+
+	var dates []time.Time
+	hour, min, sec := r.Dtstart.Clock()
+	for date := start; date.Before(end); date = date.AddDate(0, 0, 1) {
+		if len(byMonth) > 0 {
+			found := false
+			for _, month := range byMonth {
+				found = found || month == date.Month()
+			}
+			if !found {
+				continue
+			}
+		}
+		if len(byMonthDay) > 0 && !matchMonthDay(date, byMonthDay) {
+			continue
+		}
+		if len(byDay) > 0 && !matchWeekday(date, byDay, inYear) {
+			continue
+		}
+		dates = append(dates, time.Date(date.Year(), date.Month(), date.Day(), hour, min, sec, r.Dtstart.Nanosecond(), r.Dtstart.Location()))
+	}
+
+	if len(r.BySetPos) > 0 {
+		var selected []time.Time
+		for _, pos := range r.BySetPos {
+			i := pos - 1
+			if pos < 0 {
+				i = len(dates) + pos
+			}
+			if i >= 0 && i < len(dates) {
+				selected = append(selected, dates[i])
+			}
+		}
+		sort.Slice(selected, func(i, j int) bool { return selected[i].Before(selected[j]) })
+		dates = selected
+	}
+	return dates
+}
+
+// The number of periods between the period of Dtstart and the period of the given date
+func (r RRule) periodsUntil(date time.Time) int {
+	y1, m1, _ := r.Dtstart.Date()
+	y2, m2, _ := date.Date()
+	switch r.Freq {
+	case Yearly:
+		return (y2 - y1) / r.Interval
+	case Monthly:
+		return ((y2-y1)*12 + int(m2) - int(m1)) / r.Interval
+	case Weekly:
+		return actualDays(r.Dtstart, date) / 7 / r.Interval
+	}
+	return actualDays(r.Dtstart, date) / r.Interval
+}
+
+// The maximum number of periods to expand, so that rules that never match stop
+const maxPeriods = 100000
+
+// Between returns the occurrences from and including from, to and excluding to
+func (r RRule) Between(from, to time.Time) []time.Time {
+	var dates []time.Time
+	if r.Interval < 1 {
+		r.Interval = 1
+	}
+	// Without COUNT, there is no need to expand the periods before from
+	n := 0
+	if r.Count == 0 && from.After(r.Dtstart) {
+		if n = r.periodsUntil(from) - 1; n < 0 {
+			n = 0
+		}
+	}
+	count := 0
+	for ; n < maxPeriods; n++ {
+		// Allow for time zones that are ahead of UTC
+		if r.periodStart(n).AddDate(0, 0, -1).After(to) {
+			break
+		}
+		for _, date := range r.period(n) {
+			if date.Before(r.Dtstart) {
+				continue
+			}
+			if (!r.Until.IsZero() && date.After(r.Until)) || (r.Count > 0 && count >= r.Count) {
+				return dates
+			}
+			count++
+			if !date.Before(from) && date.Before(to) {
+				dates = append(dates, date)
+			}
+		}
+	}
+	return dates
+}
+
+// A Recurrence is a set of recurrence rules, with extra dates (RDATE) and
+// excluded dates (EXDATE), like the recurrence of an iCalendar event
+type Recurrence struct {
+	Dtstart time.Time
+	RRules  []RRule
+	RDates  []time.Time
+	ExDates []time.Time // excluded times, that exclude an occurrence at exactly that time
+	ExDays  []time.Time // excluded dates (VALUE=DATE), that exclude every occurrence on the date
+}
+
+// Parse a property with date values, like "EXDATE;TZID=Europe/Oslo:20250101T090000,20250108T090000".
+// Returns true if the values are dates without a time of day.
+func parseDateProperty(line string, loc *time.Location) ([]time.Time, bool, error) {
+	i := strings.Index(line, ":")
+	if i < 0 {
+		return nil, false, errors.New("Invalid property: " + line)
+	}
+	for _, param := range strings.Split(line[:i], ";")[1:] {
+		if strings.HasPrefix(strings.ToUpper(param), "TZID=") {
+			l, err := time.LoadLocation(param[5:])
+			if err != nil {
+				return nil, false, errors.New("Unknown time zone: " + param[5:])
+			}
+			loc = l
+		}
+	}
+	var (
+		dates    []time.Time
+		dateOnly bool
+	)
+	for _, value := range strings.Split(line[i+1:], ",") {
+		t, isDate, err := parseICSTime(strings.TrimSpace(value), loc)
+		if err != nil {
+			return nil, false, errors.New("Invalid date: " + value)
+		}
+		dates = append(dates, t)
+		dateOnly = isDate
+	}
+	return dates, dateOnly, nil
+}
+
+// ParseRecurrence parses RRULE, RDATE and EXDATE lines for an event that
+// starts at the given time, like "RRULE:FREQ=YEARLY" or "EXDATE:20250101".
+// DTSTART is always the first occurrence.
+func ParseRecurrence(dtstart time.Time, lines ...string) (Recurrence, error) {
+	rec := Recurrence{Dtstart: dtstart}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		name := strings.ToUpper(line)
+		if i := strings.IndexAny(name, ";:"); i >= 0 {
+			name = name[:i]
+		}
+		switch name {
+		case "RRULE":
+			r, err := ParseRRule(line, dtstart)
+			if err != nil {
+				return rec, err
+			}
+			rec.RRules = append(rec.RRules, r)
+		case "RDATE", "EXDATE":
+			dates, isDate, err := parseDateProperty(line, dtstart.Location())
+			if err != nil {
+				return rec, err
+			}
+			switch {
+			case name == "RDATE":
+				rec.RDates = append(rec.RDates, dates...)
+			case isDate:
+				rec.ExDays = append(rec.ExDays, dates...)
+			default:
+				rec.ExDates = append(rec.ExDates, dates...)
+			}
+		default:
+			return rec, errors.New("Unsupported recurrence property: " + line)
+		}
+	}
+	return rec, nil
+}
+
+// Checks if the given time is excluded by EXDATE
+func (rec Recurrence) excluded(t time.Time) bool {
+	for _, ex := range rec.ExDates {
+		if t.Equal(ex) {
+			return true
+		}
+	}
+	y1, m1, d1 := t.Date()
+	for _, ex := range rec.ExDays {
+		if y2, m2, d2 := ex.Date(); y1 == y2 && m1 == m2 && d1 == d2 {
+			return true
+		}
+	}
+	return false
+}
+
+// Between returns the occurrences from and including from, to and excluding
+// to, in order and without duplicates
+func (rec Recurrence) Between(from, to time.Time) []time.Time {
+	candidates := append([]time.Time{}, rec.RDates...)
+	if !rec.Dtstart.IsZero() {
+		candidates = append(candidates, rec.Dtstart)
+	}
+	for _, r := range rec.RRules {
+		candidates = append(candidates, r.Between(from, to)...)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+	var dates []time.Time
+	for _, t := range candidates {
+		if t.Before(from) || !t.Before(to) || rec.excluded(t) {
+			continue
+		}
+		if len(dates) > 0 && dates[len(dates)-1].Equal(t) {
+			continue
+		}
+		dates = append(dates, t)
+	}
+	return dates
+}
+
+// Checks if there is an occurrence on the date of the given time
+func (rec Recurrence) OnDate(date time.Time) bool {
+	year, month, day := date.Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+	return len(rec.Between(start, start.AddDate(0, 0, 1))) > 0
+}
@@ -0,0 +1,95 @@
+package kal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRRule(t *testing.T) {
+	for _, tc := range []struct {
+		rule    string
+		dtstart time.Time
+		from    time.Time
+		want    []time.Time
+	}{
+		// Sprint reviews every second Wednesday
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=WE", date(2025, time.January, 8), date(2025, time.January, 1),
+			[]time.Time{date(2025, time.January, 8), date(2025, time.January, 22), date(2025, time.February, 5)}},
+		// Board meetings on the first Tuesday of each quarter
+		{"FREQ=MONTHLY;INTERVAL=3;BYDAY=1TU", date(2025, time.January, 7), date(2025, time.January, 1),
+			[]time.Time{date(2025, time.January, 7), date(2025, time.April, 1), date(2025, time.July, 1)}},
+		// A birthday on February 29th
+		{"FREQ=YEARLY", date(2024, time.February, 29), date(2025, time.January, 1),
+			[]time.Time{date(2028, time.February, 29), date(2032, time.February, 29), date(2036, time.February, 29)}},
+		// The last weekday of the month
+		{"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", date(2025, time.January, 1), date(2025, time.January, 1),
+			[]time.Time{date(2025, time.January, 31), date(2025, time.February, 28), date(2025, time.March, 31)}},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=2", date(2025, time.January, 1), date(2025, time.January, 1),
+			[]time.Time{date(2025, time.January, 31), date(2025, time.February, 28)}},
+		{"FREQ=DAILY;INTERVAL=10;UNTIL=20250121", date(2025, time.January, 1), date(2025, time.January, 1),
+			[]time.Time{date(2025, time.January, 1), date(2025, time.January, 11), date(2025, time.January, 21)}},
+		// Examples from RFC 5545: the 20th Monday of the year, and US presidential election day
+		{"FREQ=YEARLY;BYDAY=20MO", date(1997, time.May, 19), date(1997, time.January, 1),
+			[]time.Time{date(1997, time.May, 19), date(1998, time.May, 18), date(1999, time.May, 17)}},
+		{"FREQ=YEARLY;INTERVAL=4;BYMONTH=11;BYDAY=TU;BYMONTHDAY=2,3,4,5,6,7,8", date(1996, time.November, 5), date(1996, time.January, 1),
+			[]time.Time{date(1996, time.November, 5), date(2000, time.November, 7), date(2004, time.November, 2)}},
+	} {
+		r, err := ParseRRule(tc.rule, tc.dtstart)
+		if err != nil {
+			t.Errorf("%s: %v", tc.rule, err)
+			continue
+		}
+		got := r.Between(tc.from, date(2100, time.January, 1))
+		if len(got) > 3 {
+			got = got[:3]
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.rule, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if !got[i].Equal(tc.want[i]) {
+				t.Errorf("%s: got %v, want %v", tc.rule, got, tc.want)
+				break
+			}
+		}
+	}
+	for _, rule := range []string{"FREQ=HOURLY", "FREQ=DAILY;BYHOUR=9", "INTERVAL=2", "FREQ=DAILY;COUNT=2;UNTIL=20250101", "FREQ=WEEKLY;BYDAY=XX", "FREQ=WEEKLY;BYDAY=2MO"} {
+		if _, err := ParseRRule(rule, date(2025, time.January, 1)); err == nil {
+			t.Errorf("%s: expected an error", rule)
+		}
+	}
+}
+
+func TestEventCalendar(t *testing.T) {
+	rec, err := ParseRecurrence(date(2025, time.January, 8), "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=WE", "EXDATE:20250122", "RDATE:20250124")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := rec.Between(date(2025, time.January, 1), date(2025, time.February, 1))
+	if len(got) != 2 || !got[0].Equal(date(2025, time.January, 8)) || !got[1].Equal(date(2025, time.January, 24)) {
+		t.Errorf("got %v", got)
+	}
+	// Only a date excludes the whole day, a time at 00:00 is just a time
+	morning := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+	daily, err := ParseRecurrence(morning, "RRULE:FREQ=DAILY;COUNT=3", "EXDATE:20250107T000000", "EXDATE;VALUE=DATE:20250108")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := daily.Between(morning, morning.AddDate(0, 0, 3)); len(got) != 2 || !got[1].Equal(morning.AddDate(0, 0, 1)) {
+		t.Errorf("got %v", got)
+	}
+	birthday, _ := ParseRecurrence(date(1990, time.May, 17), "RRULE:FREQ=YEARLY")
+	cal := NewEventCalendar(NewNorwegianCalendar(),
+		Event{Name: "Sprint review", Recurrence: rec},
+		Event{Name: "Bursdag", Flag: true, Recurrence: birthday})
+	if desc := Describe(cal, date(2025, time.May, 17)); desc != "Grunnlovsdagen, Bursdag" {
+		t.Errorf("got %q", desc)
+	}
+	if desc := Describe(cal, date(2025, time.January, 24)); desc != "Sprint review" {
+		t.Errorf("got %q", desc)
+	}
+	if notable, _, _ := cal.NotableDay(date(2025, time.January, 22)); notable {
+		t.Error("expected 22 January to be excluded")
+	}
+}
@@ -0,0 +1,356 @@
+package kal
+
+// Schedules for batch jobs, like "09:00 on every business day", that consult a Calendar
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Schedule is a set of times of day, on the dates that match a rule.
+// Schedules are created with ParseSchedule or ParseCron.
+type Schedule struct {
+	times []time.Duration // the times of day, sorted
+	match func(date time.Time) bool
+}
+
+// Next returns the first scheduled time after the given time, in the time
+// zone of the given time. Returns the zero time if nothing is scheduled
+// within the next ten years.
+func (s Schedule) Next(after time.Time) time.Time {
+	if s.match == nil {
+		return time.Time{}
+	}
+	year, month, day := after.Date()
+	for i := 0; i < maxSearchDays; i++ {
+		date := time.Date(year, month, day+i, 0, 0, 0, 0, after.Location())
+		if !s.match(date) {
+			continue
+		}
+		for _, tod := range s.times {
+			y, m, d := date.Date()
+			if t := atTimeOfDay(y, m, d, tod, after.Location()); t.After(after) {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// Find the number of the given date among the business days of its month,
+// counting from 1, or 0 if the date is not a business day
+func businessDayOfMonth(cal Calendar, date time.Time) int {
+	if !BusinessDay(cal, date) {
+		return 0
+	}
+	count := 0
+	for d := date; d.Month() == date.Month(); d = d.AddDate(0, 0, -1) {
+		if BusinessDay(cal, d) {
+			count++
+		}
+	}
+	return count
+}
+
+// Checks if the given date is the last business day of its month
+func lastBusinessDayOfMonth(cal Calendar, date time.Time) bool {
+	if !BusinessDay(cal, date) {
+		return false
+	}
+	for d := date.AddDate(0, 0, 1); d.Month() == date.Month(); d = d.AddDate(0, 0, 1) {
+		if BusinessDay(cal, d) {
+			return false
+		}
+	}
+	return true
+}
+
+// Checks if the given date is the business day that is found by going in the
+// direction of step from the given day of the month, in the month of the date
+// or in a neighbouring month. Days after the end of a month use the last day.
+func businessDayFrom(cal Calendar, date time.Time, day, step int) bool {
+	for _, months := range []int{-1, 0, 1} {
+		first := time.Date(date.Year(), date.Month()+time.Month(months), 1, 0, 0, 0, 0, date.Location())
+		d := day
+		if last := daysInMonth(first.Year(), first.Month()); d > last {
+			d = last
+		}
+		if target, ok := searchBusinessDay(cal, first.AddDate(0, 0, d-1), step); ok && target.Equal(date) {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse ordinals like "1st", "20th" or "third", returning 0 if it is not an ordinal
+func parseOrdinal(s string) int {
+	for i, word := range []string{"first", "second", "third", "fourth", "fifth"} {
+		if s == word {
+			return i + 1
+		}
+	}
+	for _, suffix := range []string{"st", "nd", "rd", "th"} {
+		if strings.HasSuffix(s, suffix) {
+			if n, err := strconv.Atoi(strings.TrimSuffix(s, suffix)); err == nil && n >= 1 && n <= 31 {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// Parse English day names, with or without a plural s, like "monday" or "fridays"
+func parseWeekday(s string) (time.Weekday, bool) {
+	s = strings.TrimSuffix(s, "s")
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		name := strings.ToLower(weekday.String())
+		if s == name || s == name[:3] {
+			return weekday, true
+		}
+	}
+	return time.Sunday, false
+}
+
+// Matches times of day, like 09:00 or 9:30
+var timeOfDayRegexp = regexp.MustCompile(`\b(\d{1,2}):(\d{2})\b`)
+
+// ParseSchedule parses a schedule expression in English, that uses the
+// given calendar to find business days. The expression may contain times
+// of day, like "09:00" or "09:00 and 15:30", the default is 00:00.
+//
+//	Supported expressions include:
+//	09:00 on every day
+//	09:00 on every business day
+//	every weekday at 08:00 (Monday to Friday, also on public holidays)
+//	every monday and thursday at 12:00
+//	the 1st of the month
+//	the first business day of the month
+//	the 3rd business day of the month
+//	the last business day of the month
+//	the last day of the month
+//	the business day before the 20th (the 20th, or the business day before it
+//	if the 20th is not a business day, like Norwegian pay days)
+//	the business day after the 15th (the 15th, or the next business day)
+func ParseSchedule(cal Calendar, expr string) (Schedule, error) {
+	var s Schedule
+	text := strings.ToLower(expr)
+	for _, m := range timeOfDayRegexp.FindAllStringSubmatch(text, -1) {
+		hour, _ := strconv.Atoi(m[1])
+		minute, _ := strconv.Atoi(m[2])
+		if hour > 23 || minute > 59 {
+			return s, errors.New("Invalid time of day: " + m[0])
+		}
+		s.times = append(s.times, time.Duration(hour)*time.Hour+time.Duration(minute)*time.Minute)
+	}
+	if len(s.times) == 0 {
+		s.times = []time.Duration{0}
+	}
+	sort.Slice(s.times, func(i, j int) bool { return s.times[i] < s.times[j] })
+
+	// Remove the times of day and the filler words
+	text = timeOfDayRegexp.ReplaceAllString(text, " ")
+	text = strings.NewReplacer(",", " ", "of the month", " ", "in the month", " ", "each", "every").Replace(text)
+	var words []string
+	for _, word := range strings.Fields(text) {
+		switch word {
+		case "at", "on", "the", "and":
+			continue
+		}
+		words = append(words, word)
+	}
+	phrase := strings.Join(words, " ")
+
+	switch {
+	case phrase == "every day" || phrase == "daily":
+		s.match = func(date time.Time) bool { return true }
+	case phrase == "every business day":
+		s.match = func(date time.Time) bool { return BusinessDay(cal, date) }
+	case phrase == "every weekday":
+		s.match = func(date time.Time) bool { return date.Weekday() != time.Saturday && date.Weekday() != time.Sunday }
+	case phrase == "last business day":
+		s.match = func(date time.Time) bool { return lastBusinessDayOfMonth(cal, date) }
+	case phrase == "last day":
+		s.match = lastDayInMonth
+	case len(words) == 3 && words[1] == "business" && words[2] == "day" && parseOrdinal(words[0]) > 0:
+		n := parseOrdinal(words[0])
+		s.match = func(date time.Time) bool { return businessDayOfMonth(cal, date) == n }
+	case len(words) == 4 && words[0] == "business" && words[1] == "day" && (words[2] == "before" || words[2] == "after") && parseOrdinal(words[3]) > 0:
+		n, step := parseOrdinal(words[3]), -1
+		if words[2] == "after" {
+			step = 1
+		}
+		s.match = func(date time.Time) bool { return businessDayFrom(cal, date, n, step) }
+	case len(words) == 1 && parseOrdinal(words[0]) > 0:
+		n := parseOrdinal(words[0])
+		s.match = func(date time.Time) bool { return date.Day() == n }
+	case len(words) > 1 && words[0] == "every":
+		var weekdays [7]bool
+		for _, word := range words[1:] {
+			weekday, ok := parseWeekday(word)
+			if !ok {
+				return s, errors.New("Unsupported schedule: " + expr)
+			}
+			weekdays[weekday] = true
+		}
+		s.match = func(date time.Time) bool { return weekdays[date.Weekday()] }
+	default:
+		return s, errors.New("Unsupported schedule: " + expr)
+	}
+	return s, nil
+}
+
+// Parse a cron field with lists, ranges and steps, like "1,15", "1-5" or "*/15",
+// where names can be given for the values, starting with the value of min
+func parseCronField(field string, min, max int, names []string) ([]bool, error) {
+	values := make([]bool, max+1)
+	value := func(s string) (int, error) {
+		for i, name := range names {
+			if strings.EqualFold(s, name) {
+				return min + i, nil
+			}
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < min || n > max {
+			return 0, errors.New("Invalid value in cron field: " + field)
+		}
+		return n, nil
+	}
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return nil, errors.New("Invalid step in cron field: " + field)
+			}
+			step, part = n, part[:i]
+		}
+		from, to := min, max
+		if part != "*" {
+			var err error
+			bounds := strings.SplitN(part, "-", 2)
+			if from, err = value(bounds[0]); err != nil {
+				return nil, err
+			}
+			to = from
+			if len(bounds) == 2 {
+				if to, err = value(bounds[1]); err != nil {
+					return nil, err
+				}
+			} else if step > 1 {
+				to = max
+			}
+		}
+		for n := from; n <= to; n += step {
+			values[n] = true
+		}
+	}
+	return values, nil
+}
+
+// Parse the day of month field of a cron expression, with the business day
+// extensions. Returns the matching function and true if the field is a day of
+// month that is combined with the day of week field using OR, like in cron.
+// The business day extensions are combined with the day of week using AND.
+func parseCronDays(cal Calendar, field string) (func(time.Time) bool, bool, error) {
+	switch {
+	case field == "*":
+		return func(time.Time) bool { return true }, false, nil
+	case field == "B":
+		return func(date time.Time) bool { return BusinessDay(cal, date) }, false, nil
+	case field == "LB":
+		return func(date time.Time) bool { return lastBusinessDayOfMonth(cal, date) }, false, nil
+	case field == "L":
+		return lastDayInMonth, true, nil
+	case strings.HasPrefix(field, "B<") || strings.HasPrefix(field, "B>"):
+		n, err := strconv.Atoi(field[2:])
+		if err != nil || n < 1 || n > 31 {
+			return nil, false, errors.New("Invalid day of month in cron expression: " + field)
+		}
+		step := -1
+		if field[1] == '>' {
+			step = 1
+		}
+		return func(date time.Time) bool { return businessDayFrom(cal, date, n, step) }, false, nil
+	case strings.HasSuffix(field, "B"):
+		n, err := strconv.Atoi(strings.TrimSuffix(field, "B"))
+		if err != nil || n < 1 || n > 23 {
+			return nil, false, errors.New("Invalid day of month in cron expression: " + field)
+		}
+		return func(date time.Time) bool { return businessDayOfMonth(cal, date) == n }, false, nil
+	}
+	days, err := parseCronField(field, 1, 31, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	return func(date time.Time) bool { return days[date.Day()] }, true, nil
+}
+
+// ParseCron parses a standard cron expression with five fields, minute,
+// hour, day of month, month and day of week, that uses the given calendar
+// to find business days. If both the day of month and the day of week are
+// restricted, a date matches if either of them matches, like in cron. The
+// business day extensions are the exception, a date must match both the
+// extension and the day of week, so "0 9 B * mon" is 09:00 on the Mondays
+// that are business days.
+//
+//	The day of month field supports these extensions:
+//	B    every business day
+//	LB   the last business day of the month
+//	L    the last day of the month
+//	3B   the third business day of the month
+//	B<20 the 20th, or the business day before it if it's not a business day
+//	B>20 the 20th, or the business day after it if it's not a business day
+//
+// For example, "0 9 B * *" is 09:00 on every business day and
+// "30 8 B<20 * *" is 08:30 on Norwegian pay days.
+func ParseCron(cal Calendar, expr string) (Schedule, error) {
+	var s Schedule
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return s, errors.New("A cron expression must have five fields: " + expr)
+	}
+	minutes, err := parseCronField(fields[0], 0, 59, nil)
+	if err != nil {
+		return s, err
+	}
+	hours, err := parseCronField(fields[1], 0, 23, nil)
+	if err != nil {
+		return s, err
+	}
+	days, eitherDays, err := parseCronDays(cal, fields[2])
+	if err != nil {
+		return s, err
+	}
+	months, err := parseCronField(fields[3], 1, 12, []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"})
+	if err != nil {
+		return s, err
+	}
+	weekdays, err := parseCronField(fields[4], 0, 7, []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"})
+	if err != nil {
+		return s, err
+	}
+	weekdays[0] = weekdays[0] || weekdays[7] // 7 is also Sunday
+	restrictedWeekdays := fields[4] != "*"
+
+	for hour, okHour := range hours {
+		for minute, okMinute := range minutes {
+			if okHour && okMinute {
+				s.times = append(s.times, time.Duration(hour)*time.Hour+time.Duration(minute)*time.Minute)
+			}
+		}
+	}
+	s.match = func(date time.Time) bool {
+		if !months[date.Month()] {
+			return false
+		}
+		if eitherDays && restrictedWeekdays {
+			return days(date) || weekdays[date.Weekday()]
+		}
+		return days(date) && weekdays[date.Weekday()]
+	}
+	return s, nil
+}
@@ -0,0 +1,61 @@
+package kal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedule(t *testing.T) {
+	cal := NewNorwegianCalendar()
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2025, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	for _, tc := range []struct {
+		expr        string
+		cron        bool
+		after, want time.Time
+	}{
+		{"09:00 on every business day", false, at(time.April, 16, 9, 0), at(time.April, 22, 9, 0)},
+		{"0 9 B * *", true, at(time.April, 16, 9, 0), at(time.April, 22, 9, 0)},
+		{"the last business day of the month", false, at(time.May, 1, 0, 0), at(time.May, 30, 0, 0)},
+		{"0 0 LB * *", true, at(time.May, 1, 0, 0), at(time.May, 30, 0, 0)},
+		// The 20th of December 2025 is a Saturday
+		{"the business day before the 20th at 08:30", false, at(time.December, 1, 0, 0), at(time.December, 19, 8, 30)},
+		{"30 8 B<20 * *", true, at(time.December, 1, 0, 0), at(time.December, 19, 8, 30)},
+		{"the business day before the 20th at 08:30", false, at(time.November, 1, 0, 0), at(time.November, 20, 8, 30)},
+		{"the business day after the 20th", false, at(time.December, 1, 0, 0), at(time.December, 22, 0, 0)},
+		{"the first business day of the month", false, at(time.December, 31, 0, 0), time.Date(2026, time.January, 2, 0, 0, 0, 0, time.UTC)},
+		{"0 6 2B * *", true, at(time.April, 1, 0, 0), at(time.April, 2, 6, 0)},
+		{"every monday and thursday at 12:00", false, at(time.April, 14, 12, 0), at(time.April, 17, 12, 0)},
+		{"the last day of the month", false, at(time.February, 2, 0, 0), at(time.February, 28, 0, 0)},
+		{"*/15 10 * * mon-fri", true, at(time.April, 18, 10, 50), at(time.April, 21, 10, 0)},
+		{"0 12 1 * 1", true, at(time.September, 2, 0, 0), at(time.September, 8, 12, 0)},
+		// The 21st of April 2025 is Easter Monday, so B and mon must both match
+		{"0 9 B * mon", true, at(time.April, 18, 0, 0), at(time.April, 28, 9, 0)},
+	} {
+		var (
+			s   Schedule
+			err error
+		)
+		if tc.cron {
+			s, err = ParseCron(cal, tc.expr)
+		} else {
+			s, err = ParseSchedule(cal, tc.expr)
+		}
+		if err != nil {
+			t.Errorf("%q: %v", tc.expr, err)
+			continue
+		}
+		if got := s.Next(tc.after); !got.Equal(tc.want) {
+			t.Errorf("%q after %s: got %s, want %s", tc.expr, tc.after, got, tc.want)
+		}
+	}
+	for _, expr := range []string{"sometimes", "every funday", "25:00 every day"} {
+		if _, err := ParseSchedule(cal, expr); err == nil {
+			t.Errorf("%q: expected an error", expr)
+		}
+	}
+	if _, err := ParseCron(cal, "0 9 X * *"); err == nil {
+		t.Error("expected an error for an invalid cron expression")
+	}
+}
@@ -0,0 +1,289 @@
+package kal
+
+// Settlement calendars, for finding the days when payment systems are open.
+// These calendars have corresponding codes in the NewSettlementCalendar function.
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Create a new settlement calendar for a given payment system, for
+// calculating value dates. The calendar can be cached for faster lookups.
+//
+//	Supported systems, by name or by currency:
+//	TARGET2 or EUR (the euro TARGET2 system)
+//	NOK (Norwegian bank days)
+//	Fedwire or USD (the US Fedwire system)
+func NewSettlementCalendar(system string, cache bool) (cal Calendar, err error) {
+	switch strings.ToUpper(system) {
+	case "TARGET2", "EUR":
+		cal = NewTARGET2Calendar()
+	case "NOK":
+		cal = NewNorwegianBankCalendar()
+	case "FEDWIRE", "USD":
+		cal = NewFedwireCalendar()
+	default:
+		return cal, errors.New("Settlement calendar not supported: " + system)
+	}
+	if cache {
+		return NewCachedCalendar(cal), nil
+	}
+	return cal, nil
+}
+
+// Find the red day rules with the given ids in a ruleSet
+func redRules(rs *ruleSet, ids ...string) []rule {
+	var rules []rule
+	for _, r := range rs.rules {
+		for _, id := range ids {
+			if r.red && r.id == id {
+				rules = append(rules, r)
+			}
+		}
+	}
+	return rules
+}
+
+// The Monday after the dates that fall on a Sunday. This is always in the
+// same year, since none of the holidays are on the 31st of December.
+func observedOnMonday(fn dateFunc) dateFunc {
+	return func(year int) []time.Time {
+		var dates []time.Time
+		for _, date := range fn(year) {
+			if date.Weekday() == time.Sunday {
+				dates = append(dates, date.AddDate(0, 0, 1))
+			}
+		}
+		return dates
+	}
+}
+
+// --- TARGET2 ---
+
+// TARGET2Calendar is the calendar for the TARGET2 payment system for euro
+type TARGET2Calendar struct{}
+
+// Create a new TARGET2 calendar
+func NewTARGET2Calendar() TARGET2Calendar {
+	return TARGET2Calendar{}
+}
+
+// The closing days of TARGET2, in addition to weekends
+var target2Days = newRuleSet(TARGET2Calendar{}.DayName, []rule{
+
+	// Source: https://www.ecb.europa.eu/paym/target/target2/profuse/calendar/html/index.en.html
+
+	{"new_years_day", "New Year's Day", true, false, fixedDate(time.January, 1)},
+	{"good_friday", "Good Friday", true, false, easterPlus(-2)},
+	{"easter_monday", "Easter Monday", true, false, easterPlus(1)},
+	{"labour_day", "Labour Day", true, false, fixedDate(time.May, 1)},
+	{"christmas_day", "Christmas Day", true, false, fixedDate(time.December, 25)},
+	{"boxing_day", "Christmas Holiday", true, false, fixedDate(time.December, 26)},
+})
+
+// Finds the English name for a day of the week
+func (tc TARGET2Calendar) DayName(day time.Weekday) string {
+	return day.String()
+}
+
+// Finds the English name for a given month
+func (tc TARGET2Calendar) MonthName(month time.Month) string {
+	return month.String()
+}
+
+// Checks if a given date is a closing day for TARGET2, other than a weekend day.
+// Returns true/false, a description and false, since there are no flag days.
+func (tc TARGET2Calendar) RedDay(date time.Time) (bool, string, bool) {
+	return target2Days.redDay(date)
+}
+
+// There are no notable days in the TARGET2 calendar
+func (tc TARGET2Calendar) NotableDay(date time.Time) (bool, string, bool) {
+	return false, "", false
+}
+
+// There are no half days in the TARGET2 calendar
+func (tc TARGET2Calendar) HalfDay(date time.Time) (bool, time.Duration) {
+	return false, 0
+}
+
+// There are no notable periods in the TARGET2 calendar
+func (tc TARGET2Calendar) NotablePeriod(date time.Time) (bool, string) {
+	return false, ""
+}
+
+func (tc TARGET2Calendar) MondayFirst() bool {
+	return true
+}
+
+// Saturday and Sunday
+func (tc TARGET2Calendar) Weekend() []time.Weekday {
+	return saturdaySunday()
+}
+
+// An ordinary day
+func (tc TARGET2Calendar) NormalDay() string {
+	return "Business day"
+}
+
+// Describe what type of day a given date is
+func (tc TARGET2Calendar) describe(date time.Time, weekend bool) string {
+	if desc, ok := target2Days.describe(date, weekend); ok {
+		return desc
+	}
+	return tc.NormalDay()
+}
+
+// List the closing days of the given year
+func (tc TARGET2Calendar) specialDays(year int) []SpecialDay {
+	return target2Days.specialDays(year, true, false)
+}
+
+// --- Norwegian bank days ---
+
+// NorwegianBankCalendar is the calendar for Norwegian bank days. It is like
+// the Norwegian calendar, but banks are also closed all day on julaften
+// and nyttårsaften.
+type NorwegianBankCalendar struct {
+	NorwegianCalendar
+}
+
+// Create a new calendar for Norwegian bank days
+func NewNorwegianBankCalendar() NorwegianBankCalendar {
+	return NorwegianBankCalendar{}
+}
+
+// The days when Norwegian banks are closed, in addition to weekends
+var norwegianBankDays = newRuleSet(NorwegianCalendar{}.DayName, append(
+	redRules(norwegianDays, "new_years_day", "maundy_thursday", "good_friday", "easter_sunday", "easter_monday",
+		"labour_day", "constitution_day", "ascension_day", "whit_sunday", "whit_monday",
+		"christmas_eve", "christmas_day", "boxing_day"),
+	rule{"new_years_eve", norwegianCatalog.Names["new_years_eve"], true, false, fixedDate(time.December, 31)},
+))
+
+// Checks if a given date is a day when Norwegian banks are closed, other
+// than a weekend day. Returns true/false, a description and true/false for
+// if it's a flag day.
+func (bc NorwegianBankCalendar) RedDay(date time.Time) (bool, string, bool) {
+	return norwegianBankDays.redDay(date)
+}
+
+// Checks if a given date is notable in the Norwegian calendar, leaving out
+// the days that are also bank holidays, like nyttårsaften
+func (bc NorwegianBankCalendar) NotableDay(date time.Time) (bool, string, bool) {
+	notable, desc, flag := bc.NorwegianCalendar.NotableDay(date)
+	red, redDesc, _ := bc.RedDay(date)
+	if !notable || !red {
+		return notable, desc, flag
+	}
+	var descriptions []string
+	for _, d := range strings.Split(desc, ", ") {
+		if d != redDesc {
+			descriptions = append(descriptions, d)
+		}
+	}
+	if len(descriptions) == 0 {
+		return false, "", false
+	}
+	return true, strings.Join(descriptions, ", "), flag
+}
+
+// There are no half days for Norwegian banks, they are closed all day on julaften
+func (bc NorwegianBankCalendar) HalfDay(date time.Time) (bool, time.Duration) {
+	return false, 0
+}
+
+// Describe what type of day a given date is
+func (bc NorwegianBankCalendar) describe(date time.Time, weekend bool) string {
+	return describe(bc, date, weekend)
+}
+
+// List the bank holidays and the notable days of the given year, leaving
+// out the notable days that are also bank holidays, like nyttårsaften
+func (bc NorwegianBankCalendar) specialDays(year int) []SpecialDay {
+	days := norwegianBankDays.specialDays(year, true, false)
+	for _, sd := range norwegianDays.specialDays(year, false, true) {
+		if _, name, _ := bc.RedDay(sd.Date); name != sd.Name {
+			sd.Start = 0
+			days = append(days, sd)
+		}
+	}
+	sortSpecialDays(days)
+	return days
+}
+
+// --- Fedwire ---
+
+// FedwireCalendar is the calendar for the Fedwire payment system, which
+// follows the holidays of the Federal Reserve Banks
+type FedwireCalendar struct {
+	USCalendar
+}
+
+// Create a new Fedwire calendar
+func NewFedwireCalendar() FedwireCalendar {
+	return FedwireCalendar{}
+}
+
+// Juneteenth, from 2022, when the Federal Reserve Banks first closed for it
+func juneteenth(year int) []time.Time {
+	if year < 2022 {
+		return nil
+	}
+	return fixedDate(time.June, 19)(year)
+}
+
+// The holidays of the Federal Reserve Banks, in addition to weekends.
+// Holidays on a Sunday are observed the next Monday, while holidays on
+// a Saturday are not observed on the Friday before.
+var fedwireDays = newRuleSet(USCalendar{}.DayName, []rule{
+
+	// Source: https://www.frbservices.org/about/holiday-schedules
+
+	{"new_years_day", "New Year's Day", true, false, fixedDate(time.January, 1)},
+	{"new_years_day_observed", "New Year's Day (observed)", true, false, observedOnMonday(fixedDate(time.January, 1))},
+	{"martin_luther_king_day", "Birthday of Martin Luther King, Jr.", true, false, nthWeekday(3, time.Monday, time.January)},
+	{"presidents_day", "Washington's Birthday", true, false, nthWeekday(3, time.Monday, time.February)},
+	{"memorial_day", "Memorial Day", true, false, lastWeekday(time.Monday, time.May)},
+	{"juneteenth", "Juneteenth National Independence Day", true, false, juneteenth},
+	{"juneteenth_observed", "Juneteenth National Independence Day (observed)", true, false, observedOnMonday(juneteenth)},
+	{"independence_day", "Independence Day", true, false, fixedDate(time.July, 4)},
+	{"independence_day_observed", "Independence Day (observed)", true, false, observedOnMonday(fixedDate(time.July, 4))},
+	{"labor_day", "Labor Day", true, false, nthWeekday(1, time.Monday, time.September)},
+	{"columbus_day", "Columbus Day", true, false, nthWeekday(2, time.Monday, time.October)},
+	{"veterans_day", "Veterans Day", true, false, fixedDate(time.November, 11)},
+	{"veterans_day_observed", "Veterans Day (observed)", true, false, observedOnMonday(fixedDate(time.November, 11))},
+	{"thanksgiving_day", "Thanksgiving Day", true, false, nthWeekday(4, time.Thursday, time.November)},
+	{"christmas_day", "Christmas Day", true, false, fixedDate(time.December, 25)},
+	{"christmas_day_observed", "Christmas Day (observed)", true, false, observedOnMonday(fixedDate(time.December, 25))},
+})
+
+// Checks if a given date is a Federal Reserve holiday, other than a weekend
+// day. Returns true/false, a description and false, since flag days are
+// found in the US calendar instead.
+func (fc FedwireCalendar) RedDay(date time.Time) (bool, string, bool) {
+	return fedwireDays.redDay(date)
+}
+
+// There are no half days in the Fedwire calendar
+func (fc FedwireCalendar) HalfDay(date time.Time) (bool, time.Duration) {
+	return false, 0
+}
+
+// Describe what type of day a given date is
+func (fc FedwireCalendar) describe(date time.Time, weekend bool) string {
+	return describe(fc, date, weekend)
+}
+
+// List the Federal Reserve holidays and the notable days of the given year
+func (fc FedwireCalendar) specialDays(year int) []SpecialDay {
+	days := fedwireDays.specialDays(year, true, false)
+	for _, sd := range usDays.specialDays(year, false, true) {
+		sd.Start = 0
+		days = append(days, sd)
+	}
+	sortSpecialDays(days)
+	return days
+}
@@ -0,0 +1,63 @@
+package kal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSettlementCalendars(t *testing.T) {
+	tests := []struct {
+		system string
+		date   time.Time
+		open   bool
+	}{
+		// TARGET2 is closed on six days a year, and open on other holidays
+		{"TARGET2", date(2025, time.January, 1), false},
+		{"TARGET2", date(2025, time.April, 18), false}, // Good Friday
+		{"TARGET2", date(2025, time.April, 21), false}, // Easter Monday
+		{"TARGET2", date(2025, time.May, 1), false},
+		{"TARGET2", date(2025, time.May, 29), true}, // Ascension Day
+		{"TARGET2", date(2025, time.December, 24), true},
+		{"TARGET2", date(2025, time.December, 25), false},
+		{"TARGET2", date(2025, time.December, 26), false},
+		{"TARGET2", date(2025, time.December, 31), true},
+
+		// Norwegian banks are also closed on julaften and nyttårsaften
+		{"NOK", date(2025, time.April, 17), false}, // Skjærtorsdag
+		{"NOK", date(2025, time.May, 29), false},   // Kristi himmelfartsdag
+		{"NOK", date(2025, time.June, 9), false},   // Andre pinsedag
+		{"NOK", date(2025, time.December, 23), true},
+		{"NOK", date(2025, time.December, 24), false},
+		{"NOK", date(2025, time.December, 31), false},
+		{"NOK", date(2026, time.January, 2), true},
+
+		// Fedwire observes holidays on a Sunday on the Monday after, but
+		// stays open on the Friday before holidays on a Saturday
+		{"USD", date(2025, time.January, 20), false}, // Martin Luther King, Jr.
+		{"USD", date(2025, time.October, 13), false}, // Columbus Day
+		{"USD", date(2025, time.November, 27), false},
+		{"USD", date(2025, time.November, 28), true},
+		{"USD", date(2023, time.January, 2), false},  // New Year's Day on a Sunday
+		{"USD", date(2021, time.December, 31), true}, // New Year's Day on a Saturday
+		{"USD", date(2022, time.December, 26), false},
+		{"USD", date(2020, time.July, 3), true},
+		{"USD", date(2021, time.July, 5), false},
+		{"USD", date(2018, time.November, 12), false},
+		{"USD", date(2023, time.November, 10), true},
+		{"USD", date(2021, time.June, 18), true}, // before Juneteenth was a Fedwire holiday
+		{"USD", date(2022, time.June, 20), false},
+		{"USD", date(2025, time.June, 19), false},
+	}
+	for _, test := range tests {
+		cal, err := NewSettlementCalendar(test.system, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if open := BusinessDay(cal, test.date); open != test.open {
+			t.Errorf("%s %s: got open %v, want %v (%s)", test.system, test.date.Format("2006-01-02"), open, test.open, Describe(cal, test.date))
+		}
+	}
+	if _, err := NewSettlementCalendar("XYZ", false); err == nil {
+		t.Error("expected an error for an unknown settlement calendar")
+	}
+}
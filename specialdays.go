@@ -0,0 +1,95 @@
+package kal
+
+// Listing the red days and notable days of a calendar, with identifiers
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// A SpecialDay is a red day or a notable day in a calendar
+type SpecialDay struct {
+	ID    string // identifier for the day, like "easter_sunday"
+	Name  string // description of the day, in the language of the calendar
+	Date  time.Time
+	Red   bool          // red day (public holiday) or just a notable day
+	Flag  bool          // flag flying day
+	Start time.Duration // the time of day when a partial day off starts, 0 for none
+}
+
+// specialDayLister is implemented by calendars that can list the special
+// days of a year, with the identifiers from their rules
+type specialDayLister interface {
+	specialDays(year int) []SpecialDay
+}
+
+// Create an identifier from the name of a day, like "board_meeting" for
+// "Board meeting"
+func specialDayID(name string) string {
+	var sb strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if underscore && sb.Len() > 0 {
+				sb.WriteRune('_')
+			}
+			sb.WriteRune(r)
+			underscore = false
+		} else {
+			underscore = true
+		}
+	}
+	return sb.String()
+}
+
+// Sort the special days by date, keeping the order of the days on the same date
+func sortSpecialDays(days []SpecialDay) {
+	sort.SliceStable(days, func(i, j int) bool { return days[i].Date.Before(days[j].Date) })
+}
+
+// Find the special days of a year, by looking up every day of the year.
+// This is used for calendars that can not list their special days.
+func lookupSpecialDays(cal Calendar, year int) []SpecialDay {
+	var days []SpecialDay
+	for date := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC); date.Year() == year; date = date.AddDate(0, 0, 1) {
+		_, start := HalfDay(cal, date)
+		red, desc, flag := cal.RedDay(date)
+		if red {
+			days = append(days, SpecialDay{specialDayID(desc), desc, date, true, flag, start})
+		}
+		if notable, desc, flag := cal.NotableDay(date); notable {
+			if red {
+				start = 0
+			}
+			days = append(days, SpecialDay{specialDayID(desc), desc, date, false, flag, start})
+		}
+	}
+	return days
+}
+
+// Find the special days of the given calendar and year
+func calendarSpecialDays(cal Calendar, year int) []SpecialDay {
+	if sl, ok := cal.(specialDayLister); ok {
+		return sl.specialDays(year)
+	}
+	return lookupSpecialDays(cal, year)
+}
+
+// SpecialDays returns the red days and the notable days of the calendar,
+// other than weekend days, from and including the date of from, to and
+// including the date of to. The days are sorted by date. When several
+// notable days fall on the same date, there is one SpecialDay for each.
+func SpecialDays(cal Calendar, from, to time.Time) []SpecialDay {
+	first, last := dateSpan(from, to, time.UTC)
+	var days []SpecialDay
+	for year := first.Year(); year <= last.Year(); year++ {
+		for _, sd := range calendarSpecialDays(cal, year) {
+			if !sd.Date.Before(first) && !sd.Date.After(last) {
+				days = append(days, sd)
+			}
+		}
+	}
+	return days
+}
@@ -0,0 +1,277 @@
+package kal
+
+// Precomputed per-year tables for fast lookups of red days and notable days
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// The number of compiled years that are kept for each ruleSet
+const tableSlots = 64
+
+// A dateFunc returns the dates that a rule falls on, for a given year.
+// The time of day and the time zone of the returned dates are ignored.
+type dateFunc func(year int) []time.Time
+
+// A rule describes a red day or a notable day
+type rule struct {
+	id    string   // identifier for the day, like "easter_sunday"
+	name  string   // description of the day
+	red   bool     // red day (public holiday) or just a notable day
+	flag  bool     // flag flying day
+	dates dateFunc // the dates this rule falls on, for a given year
+}
+
+// A dayEntry is a compact description of a single day in a yearTable.
+// The description fields are indices into the names of the table, where 0 is "".
+type dayEntry struct {
+	red         uint16 // red day description
+	notable     uint16 // notable day description
+	describe    uint16 // description of the day, if it is red or notable
+	weekendDesc uint16 // description of the day, if it is a weekend day
+	start       uint16 // minutes after midnight when a partial day off starts, 0 for none
+	redFlag     bool   // flag flying day, if it is a red day
+	notableFlag bool   // flag flying day, if it is a notable day
+}
+
+// A yearTable is a compiled year, indexed by the day of the year, starting at 0
+type yearTable struct {
+	year  int
+	names []string
+	days  [366]dayEntry
+}
+
+// A ruleSet is a list of rules for a calendar, together with some of the
+// years that have been compiled from the rules.
+// It is safe for concurrent use by multiple goroutines. A compiled year is
+// kept in the slot for the year modulo tableSlots, until another year that
+// uses the same slot is looked up. This keeps the memory use bounded, and
+// lookups do not need to take a lock.
+type ruleSet struct {
+	rules    []rule
+	weekdays [7]string                // capitalized names of the days of the week
+	halfDays map[string]time.Duration // rule ids for partial days off, and when they start
+	years    [tableSlots]atomic.Pointer[yearTable]
+}
+
+// Create a new ruleSet. For red days, later rules take precedence over
+// earlier rules. For notable days, the descriptions of all matching rules
+// are joined with ", ", in the order of the rules. dayName is used for
+// describing weekend days that are not red days.
+func newRuleSet(dayName func(time.Weekday) string, rules []rule) *ruleSet {
+	rs := &ruleSet{rules: rules}
+	for i := range rs.weekdays {
+		rs.weekdays[i] = capitalize(dayName(time.Weekday(i)))
+	}
+	return rs
+}
+
+// Mark the rules with the given ids as partial days off, where the time off
+// starts at the given time of day, like 12 * time.Hour for 12:00.
+// Returns the ruleSet, for use when declaring it.
+func (rs *ruleSet) withHalfDays(starts map[string]time.Duration) *ruleSet {
+	rs.halfDays = starts
+	return rs
+}
+
+// Add a name to the table, returning its index
+func (yt *yearTable) add(name string) uint16 {
+	for i, n := range yt.names {
+		if n == name {
+			return uint16(i)
+		}
+	}
+	yt.names = append(yt.names, name)
+	return uint16(len(yt.names) - 1)
+}
+
+// Compile the rules for the given year into a table
+func (rs *ruleSet) compile(year int) *yearTable {
+	yt := &yearTable{year: year, names: []string{""}}
+	var (
+		red     [366]string
+		notable [366][]string
+		fullDay [366]bool // a full day off, that takes precedence over partial days off
+	)
+	for _, r := range rs.rules {
+		for _, date := range r.dates(year) {
+			// Skip dates that belong to the previous or the next year
+			if date.Year() != year {
+				continue
+			}
+			i := date.YearDay() - 1
+			if start, ok := rs.halfDays[r.id]; ok {
+				// If there are several partial days off, the earliest one counts
+				if minutes := uint16(start / time.Minute); yt.days[i].start == 0 || minutes < yt.days[i].start {
+					yt.days[i].start = minutes
+				}
+			} else if r.red {
+				fullDay[i] = true
+			}
+			if r.red {
+				red[i] = r.name
+				yt.days[i].redFlag = yt.days[i].redFlag || r.flag
+			} else {
+				notable[i] = append(notable[i], r.name)
+				yt.days[i].notableFlag = yt.days[i].notableFlag || r.flag
+			}
+		}
+	}
+	for i := range yt.days {
+		if fullDay[i] {
+			yt.days[i].start = 0
+		}
+		desc := strings.Join(notable[i], ", ")
+		yt.days[i].red = yt.add(red[i])
+		yt.days[i].notable = yt.add(desc)
+		if red[i] != "" && desc != "" {
+			desc = red[i] + ", " + desc
+		} else if red[i] != "" {
+			desc = red[i]
+		}
+		yt.days[i].describe = yt.add(desc)
+		// A weekend day that is not a red day is described by its name
+		if red[i] == "" {
+			weekday := time.Date(year, time.January, 1+i, 0, 0, 0, 0, time.UTC).Weekday()
+			desc = rs.weekdays[weekday]
+			if len(notable[i]) > 0 {
+				desc += ", " + strings.Join(notable[i], ", ")
+			}
+			yt.days[i].weekendDesc = yt.add(desc)
+		}
+	}
+	return yt
+}
+
+// Find the table for the given year, compiling it if it is not in its slot.
+// Two goroutines may compile the same year at the same time, which is fine,
+// since the tables are equal.
+func (rs *ruleSet) table(year int) *yearTable {
+	slot := &rs.years[(year%tableSlots+tableSlots)%tableSlots]
+	if yt := slot.Load(); yt != nil && yt.year == year {
+		return yt
+	}
+	yt := rs.compile(year)
+	slot.Store(yt)
+	return yt
+}
+
+// Look up the table entry for a given date
+func (rs *ruleSet) day(date time.Time) (*yearTable, *dayEntry) {
+	yt := rs.table(date.Year())
+	return yt, &yt.days[date.YearDay()-1]
+}
+
+// Checks if a given date is a red day, according to the rules
+func (rs *ruleSet) redDay(date time.Time) (bool, string, bool) {
+	yt, de := rs.day(date)
+	if de.red == 0 {
+		return false, "", false
+	}
+	return true, yt.names[de.red], de.redFlag
+}
+
+// Checks if a given date is a notable day, according to the rules
+func (rs *ruleSet) notableDay(date time.Time) (bool, string, bool) {
+	yt, de := rs.day(date)
+	if de.notable == 0 {
+		return false, "", false
+	}
+	return true, yt.names[de.notable], de.notableFlag
+}
+
+// Checks if a given date is a partial day off, according to the rules.
+// Returns true and the time of day when the time off starts, or false.
+func (rs *ruleSet) halfDay(date time.Time) (bool, time.Duration) {
+	_, de := rs.day(date)
+	if de.start == 0 {
+		return false, 0
+	}
+	return true, time.Duration(de.start) * time.Minute
+}
+
+// Describe a given date, according to the rules and if the date is
+// a weekend day or not. Returns false if the day is an ordinary one.
+func (rs *ruleSet) describe(date time.Time, weekend bool) (string, bool) {
+	yt, de := rs.day(date)
+	if weekend && de.weekendDesc != 0 {
+		return yt.names[de.weekendDesc], true
+	}
+	if de.describe == 0 {
+		return "", false
+	}
+	return yt.names[de.describe], true
+}
+
+// List the red days, the notable days or both for the given year, sorted by
+// date and then by the order of the rules. A red day that is overridden by
+// a later rule on the same date is left out.
+func (rs *ruleSet) specialDays(year int, red, notable bool) []SpecialDay {
+	var days []SpecialDay
+	for _, r := range rs.rules {
+		if (r.red && !red) || (!r.red && !notable) {
+			continue
+		}
+		for _, date := range r.dates(year) {
+			if date.Year() != year {
+				continue
+			}
+			date = time.Date(year, date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+			if r.red {
+				if _, name, _ := rs.redDay(date); name != r.name {
+					continue
+				}
+			}
+			sd := SpecialDay{ID: r.id, Name: r.name, Date: date, Red: r.red, Flag: r.flag}
+			if _, ok := rs.halfDays[r.id]; ok {
+				_, sd.Start = rs.halfDay(date)
+			}
+			days = append(days, sd)
+		}
+	}
+	sortSpecialDays(days)
+	return days
+}
+
+// --- Functions for creating dateFuncs ---
+
+// A given month and day, every year
+func fixedDate(month time.Month, day int) dateFunc {
+	return func(year int) []time.Time {
+		return []time.Time{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+	}
+}
+
+// Easter day +- a few days
+func easterPlus(days int) dateFunc {
+	return func(year int) []time.Time {
+		return []time.Time{EasterDay(year).AddDate(0, 0, days)}
+	}
+}
+
+// The Nth weekday of a given month, like the 3rd Monday in January
+func nthWeekday(n int, weekday time.Weekday, month time.Month) dateFunc {
+	return func(year int) []time.Time {
+		date, err := nthWeekdayOfMonth(time.Date(year, month, 1, 0, 0, 0, 0, time.UTC), n, weekday)
+		if err != nil {
+			return nil
+		}
+		return []time.Time{date}
+	}
+}
+
+// The last weekday of a given month, like the last Monday in May
+func lastWeekday(weekday time.Weekday, month time.Month) dateFunc {
+	return func(year int) []time.Time {
+		return []time.Time{lastDayOfMonth(time.Date(year, month, 1, 0, 0, 0, 0, time.UTC), weekday)}
+	}
+}
+
+// A single date per year, as returned by the given function
+func yearly(fn func(year int) time.Time) dateFunc {
+	return func(year int) []time.Time {
+		return []time.Time{fn(year)}
+	}
+}
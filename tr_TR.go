@@ -6,12 +6,19 @@ package kal
 // This calendar has a corresponding locale code in the NewCalendar function in calendar.go
 
 import (
+	"errors"
+	"fmt"
 	"time"
 )
 
 type TRCalendar struct{}
 
-// Create a new US calendar
+// ErrNoData is returned for the years that a calendar has no data for
+var ErrNoData = errors.New("no data for the year")
+
+// Create a new TR calendar. Arife, the half day before Ramazan Bayramı and
+// Kurban Bayramı, is only known for the years with dates announced by
+// Diyanet, see CheckYear.
 func NewTRCalendar() TRCalendar {
 	return TRCalendar{}
 }
@@ -19,95 +26,122 @@ func NewTRCalendar() TRCalendar {
 // Finds the TR name for a day of the week.
 // Note that time.Weekday starts at 0 with Sunday, not Monday.
 func (tc TRCalendar) DayName(day time.Weekday) string {
-	return []string{"pazar", "pazartesi", "salı", "çarşamba", "perşembe", "cuma", "cumartesi"}[int(day)]
+	return turkishCatalog.DayName(day)
 }
 
 // Finds the english name for a day of the week.
 // Note that time.Weekday starts at 0 with Sunday, not Monday.
+//
+// Deprecated: Use NewCalendar("en_TR", false) for a Turkish calendar with English names.
 func (tc TRCalendar) DayNameInEnglish(day time.Weekday) string {
-	return day.String()
+	return englishCatalog.DayName(day)
 }
 
 // Finds the TR name for a given month
 func (tc TRCalendar) MonthName(month time.Month) string {
-	return []string{"ocak", "şubat", "mart", "nisan", "mayıs", "haziran", "temmuz", "ağustos", "eylül", "ekim", "kasım", "aralık"}[int(month)-1]
+	return turkishCatalog.MonthName(month)
 }
 
 // Finds the english name for a given month
+//
+// Deprecated: Use NewCalendar("en_TR", false) for a Turkish calendar with English names.
 func (tc TRCalendar) MonthNameInEnglish(month time.Month) string {
-	return month.String()
+	return englishCatalog.MonthName(month)
 }
 
-// Checks if a given date is a "red day" (public holiday) in the TR calendar.
-// Returns true/false, a description and true/false for if it's a flag day.
-func (tc TRCalendar) RedDay(date time.Time) (bool, string, bool) {
+// The red days and notable days in Turkey. The names are in the catalogs.
+var trRules = []rule{
 
 	// Source: https://en.wikipedia.org/wiki/Public_holidays_in_Turkey
 
-	var (
-		desc string
-		flag bool
-	)
-
-	// Sundays
-	if date.Weekday() == time.Sunday {
-		desc = "Pazar"
-	}
-
 	// New Year's Day
-	if atMD(date, 1, 1) {
-		desc = "Yılbaşı"
-		flag = true
-	}
+	{"new_years_day", "", true, true, fixedDate(time.January, 1)},
 
 	// National sovereignty and children's day
-	if atMD(date, 4, 23) {
-		desc = "Ulusal Egemenlik ve Çocuk Bayramı"
-		flag = true
-	}
+	{"national_sovereignty_day", "", true, true, fixedDate(time.April, 23)},
 
 	// Labor and Solidarity Day
-	if atMD(date, 5, 1) {
-		desc = "İşçi Bayramı"
-		flag = true
-	}
+	{"labour_day", "", true, true, fixedDate(time.May, 1)},
 
 	// Commemoration of Atatürk, Youth and Sports Day
-	if atMD(date, 5, 19) {
-		desc = "Atatürk'ü Anma, Gençlik ve Spor Bayramı"
-		flag = true
-	}
+	{"ataturk_commemoration", "", true, true, fixedDate(time.May, 19)},
 
 	// Democracy and National Unity Day
-	if atMD(date, 7, 15) {
-		desc = "Demokrasi ve Milli Birlik Günü"
-		flag = true
-	}
+	{"democracy_day", "", true, true, fixedDate(time.July, 15)},
 
 	// Victory Day
-	if atMD(date, 8, 30) {
-		desc = "Zafer Bayramı"
-		flag = true
-	}
+	{"victory_day", "", true, true, fixedDate(time.August, 30)},
 
 	// Republic Day
-	if atMD(date, 10, 29) {
-		desc = "Cumhuriyet Bayramı"
-		flag = true
-	}
+	{"republic_day", "", true, true, fixedDate(time.October, 29)},
+
+	// --- Notable days ---
 
-	/**
-	 * TODO: calculation for Ramadan Feast and Sacrifice Day will be added.
-	 */
+	// Since days may overlap, "flaggdager" must come first for the flag
+	// flying days to be correct.
 
-	// Red days
-	if desc != "" {
-		// Then return
-		return true, desc, flag
+	// --- Flag days ---
+
+	// --- Non-flag days ---
+
+	// Arife, the day before Ramazan Bayramı and Kurban Bayramı, is a half
+	// day off from 13:00. The feasts themselves are not included yet.
+	{"ramadan_feast_eve", "", false, false, arife(trRamazanBayrami)},
+	{"sacrifice_feast_eve", "", false, false, arife(trKurbanBayrami)},
+}
+
+// The partial days off in Turkey, and when the time off starts
+var trHalfDays = map[string]time.Duration{
+	"ramadan_feast_eve":   13 * time.Hour, // Ramazan Bayramı Arifesi
+	"sacrifice_feast_eve": 13 * time.Hour, // Kurban Bayramı Arifesi
+}
+
+// The red days and notable days in the TR calendar, with the Turkish names
+var trDays = localRules(regions["TR"], turkishCatalog)
+
+// The first days of Ramazan Bayramı and Kurban Bayramı, as announced by
+// Diyanet. The feasts follow the lunar Islamic calendar, so there is no
+// arife for the years that are not listed here.
+var (
+	trRamazanBayrami = map[int]time.Time{
+		2020: time.Date(2020, time.May, 24, 0, 0, 0, 0, time.UTC),
+		2021: time.Date(2021, time.May, 13, 0, 0, 0, 0, time.UTC),
+		2022: time.Date(2022, time.May, 2, 0, 0, 0, 0, time.UTC),
+		2023: time.Date(2023, time.April, 21, 0, 0, 0, 0, time.UTC),
+		2024: time.Date(2024, time.April, 10, 0, 0, 0, 0, time.UTC),
+		2025: time.Date(2025, time.March, 30, 0, 0, 0, 0, time.UTC),
+		2026: time.Date(2026, time.March, 20, 0, 0, 0, 0, time.UTC),
+	}
+	trKurbanBayrami = map[int]time.Time{
+		2020: time.Date(2020, time.July, 31, 0, 0, 0, 0, time.UTC),
+		2021: time.Date(2021, time.July, 20, 0, 0, 0, 0, time.UTC),
+		2022: time.Date(2022, time.July, 9, 0, 0, 0, 0, time.UTC),
+		2023: time.Date(2023, time.June, 28, 0, 0, 0, 0, time.UTC),
+		2024: time.Date(2024, time.June, 16, 0, 0, 0, 0, time.UTC),
+		2025: time.Date(2025, time.June, 6, 0, 0, 0, 0, time.UTC),
+		2026: time.Date(2026, time.May, 27, 0, 0, 0, 0, time.UTC),
 	}
+)
+
+// The day before the first day of a feast, from the announced dates
+func arife(feast map[int]time.Time) dateFunc {
+	return func(year int) []time.Time {
+		var dates []time.Time
+		// The feast of the next year may start on the 1st of January
+		for y := year; y <= year+1; y++ {
+			if date, ok := feast[y]; ok {
+				dates = append(dates, date.AddDate(0, 0, -1))
+			}
+		}
+		return dates
+	}
+}
 
-	// Normal days
-	return false, desc, false
+// Checks if a given date is a "red day" (public holiday) in the TR calendar.
+// Returns true/false, a description and true/false for if it's a flag day.
+// Weekend days are not red days, unless they are also public holidays.
+func (tc TRCalendar) RedDay(date time.Time) (bool, string, bool) {
+	return trDays.redDay(date)
 }
 
 // Some days are not red, but special in one way or another.
@@ -116,21 +150,41 @@ func (tc TRCalendar) RedDay(date time.Time) (bool, string, bool) {
 // than one notable event that day) and true/false depending on if it's a flag
 // flying day or not.
 func (tc TRCalendar) NotableDay(date time.Time) (bool, string, bool) {
+	return trDays.notableDay(date)
+}
 
-	var (
-	//descriptions []string
-	//flag         bool
-	)
-
-	// Since days may overlap, "flaggdager" must come first for the flag
-	// flying days to be correct.
+// Checks if a given date is a partial day off in the TR calendar, where only
+// the part of the day after a given time of day is off. Returns true/false
+// and the time of day when the time off starts, like 12 hours for 12:00.
+// Returns false for arife in the years that CheckYear has no data for.
+func (tc TRCalendar) HalfDay(date time.Time) (bool, time.Duration) {
+	return trDays.halfDay(date)
+}
 
-	// --- Flag days ---
+// CheckYear returns an error that wraps ErrNoData if the dates of Ramazan
+// Bayramı and Kurban Bayramı are not known for the given year, so that
+// HalfDay can not find arife that year
+func (tc TRCalendar) CheckYear(year int) error {
+	if _, ok := trRamazanBayrami[year]; !ok {
+		return fmt.Errorf("%w: Ramazan Bayramı in %d has not been announced", ErrNoData, year)
+	}
+	if _, ok := trKurbanBayrami[year]; !ok {
+		return fmt.Errorf("%w: Kurban Bayramı in %d has not been announced", ErrNoData, year)
+	}
+	return nil
+}
 
-	// --- Non-flag days ---
+// Describe what type of day a given date is
+func (tc TRCalendar) describe(date time.Time, weekend bool) string {
+	if desc, ok := trDays.describe(date, weekend); ok {
+		return desc
+	}
+	return tc.NormalDay()
+}
 
-	// No notable events
-	return false, "", false
+// List the red days and notable days of the given year
+func (tc TRCalendar) specialDays(year int) []SpecialDay {
+	return trDays.specialDays(year, true, true)
 }
 
 // Checks if a given date is in a notable time range (summer holidays, for instance)
@@ -141,11 +195,26 @@ func (tc TRCalendar) NotablePeriod(date time.Time) (bool, string) {
 	return false, ""
 }
 
+// Saturday and Sunday
+func (tc TRCalendar) Weekend() []time.Weekday {
+	return saturdaySunday()
+}
+
 func (tc TRCalendar) MondayFirst() bool {
 	return true
 }
 
 // An ordinary day
 func (tc TRCalendar) NormalDay() string {
-	return "Sıradan"
+	return turkishCatalog.NormalDay
+}
+
+// The Turkish words for the parts of a period
+func (tc TRCalendar) periodWords() periodWords {
+	return turkishCatalog.periodWords()
+}
+
+// The Turkish names
+func (tc TRCalendar) catalog() *Catalog {
+	return turkishCatalog
 }
@@ -4,16 +4,17 @@ import (
 	"errors"
 	"fmt"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
-// Checks if the given time is at the given month and day
-func atMD(date time.Time, month, day int) bool {
-	return (date.Month() == time.Month(month)) && (date.Day() == day)
-}
-
-// Checks if the two given times are at the same months and days
-func atDate(t, when time.Time) bool {
-	return (t.Month() == when.Month()) && (t.Day() == when.Day())
+// Make the first letter of a string uppercase
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
 }
 
 // Return the count of a given weekday from day t, +- a few days
@@ -43,33 +44,13 @@ func sundaysInPeriod(date time.Time, days int) int {
 	return numberOfWeekdaysInPeriod(date, days, time.Sunday)
 }
 
-// Find a preceding sunday, same year
-func searchBackwardsForSunday(date time.Time) (time.Time, error) {
-	return searchBackwardsForDaySameYear(date, time.Sunday)
-}
-
 // Find the last weekday given a month/year
 func lastDayOfMonth(date time.Time, weekday time.Weekday) time.Time {
+	// Start with the last day in the given month
+	last := time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC)
 
-	var found time.Time
-
-	// Start with the first day in the given month
-	current := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
-
-	// Stay within the same month
-	for current.Month() == date.Month() {
-		// Check if it's the given weekday
-		if current.Weekday() == weekday {
-			// Found one
-			found = current
-		}
-
-		// Go the next day
-		current = current.AddDate(0, 0, 1)
-	}
-
-	// Return the last found day
-	return found
+	// Go back to the given weekday
+	return last.AddDate(0, 0, -((7 + int(last.Weekday()) - int(weekday)) % 7))
 }
 
 // Find a later weekday, same month
@@ -112,58 +93,20 @@ func searchBackwardsForDaySameYear(date time.Time, weekday time.Weekday) (time.T
 	return date, errors.New("Could not find an earlier " + weekday.String() + " the same year!")
 }
 
-// Check if the given date is at the Nth weekday (for istance Sunday) of a given month
-func atNthWeekdayOfMonth(date time.Time, n int, weekday time.Weekday, month time.Month) bool {
-	if date.Month() != month {
-		return false
-	}
-	nthDay, err := nthWeekdayOfMonth(date, n, weekday)
-	if err != nil {
-		return false
-	}
-	if atDate(date, nthDay) {
-		return true
-	}
-	return false
-}
-
-// Check if the given date is at the last type of weekday (like monday)
-func atLastWeekday(date time.Time, weekday time.Weekday, month time.Month) bool {
-	return (date.Month() == month) && atDate(date, lastDayOfMonth(date, weekday))
-}
-
 // Find the Nth type of weekday of a given year and month
 func nthWeekdayOfMonth(date time.Time, n int, whichWeekday time.Weekday) (time.Time, error) {
-
-	specialWeekdayCounter := 0
-
 	// Start at the first day in the given month
-	current := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	first := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
 
-	// As long as we are in the same month
-	for current.Month() == date.Month() {
-
-		// Which weekday is it?
-		if current.Weekday() == whichWeekday {
-			specialWeekdayCounter++
-		}
+	// Advance to the first occurrence of the given weekday, then N-1 weeks forward
+	current := first.AddDate(0, 0, (7+int(whichWeekday)-int(first.Weekday()))%7+7*(n-1))
 
-		// Is it the Nth occurrence?
-		if specialWeekdayCounter == n {
-			return current, nil
-		}
-
-		// If it's the given weekday, advance almost one week forward
-		if current.Weekday() == whichWeekday {
-			current = current.AddDate(0, 0, 7)
-			continue
-		}
-
-		// Advance to the next day
-		current = current.AddDate(0, 0, 1)
+	// Check that we are still in the same month
+	if n < 1 || current.Month() != date.Month() {
+		return date, fmt.Errorf("could not find the %dth %s in %s", n, whichWeekday, date.Month())
 	}
 
-	return date, fmt.Errorf("could not find the %dth %s in %s", n, whichWeekday, date.Month())
+	return current, nil
 }
 
 // Find the Nth sunday of a given year and month
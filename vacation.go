@@ -0,0 +1,186 @@
+package kal
+
+// Planning vacations around weekends, public holidays and bridge days
+
+import (
+	"time"
+)
+
+// BridgeDays returns the business days in the given year that are squeezed
+// between a public holiday and another day off, like a weekend. In Norway,
+// these are known as "inneklemte dager".
+func BridgeDays(cal Calendar, year int) []time.Time {
+	var days []time.Time
+	for date := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC); date.Year() == year; date = date.AddDate(0, 0, 1) {
+		if !BusinessDay(cal, date) {
+			continue
+		}
+		before, after := date.AddDate(0, 0, -1), date.AddDate(0, 0, 1)
+		if BusinessDay(cal, before) || BusinessDay(cal, after) {
+			continue
+		}
+		if Holiday(cal, before) || Holiday(cal, after) {
+			days = append(days, date)
+		}
+	}
+	return days
+}
+
+// A VacationBlock is a period of consecutive days off, made by taking
+// vacation on the business days within it
+type VacationBlock struct {
+	Start        time.Time
+	End          time.Time   // the last day off, inclusive
+	DaysOff      int         // the number of consecutive days off
+	Leave        []time.Time // the business days that are taken as vacation
+	VacationDays float64     // the vacation days that are used, where a half day counts as 0.5
+}
+
+// A VacationPlan is a set of vacation blocks
+type VacationPlan struct {
+	Blocks       []VacationBlock
+	DaysOff      int     // the days off in all the blocks
+	VacationDays float64 // the vacation days that are used in all the blocks
+}
+
+// PlanOptions are the options for planning a vacation
+type PlanOptions struct {
+	MinBlock int // the minimum number of consecutive days off in a block, 0 for no minimum
+}
+
+// The maximum number of vacation days that can be planned for
+const maxVacationDays = 60
+
+// PlanVacation places the given number of vacation days in the given year,
+// so that the number of days off in blocks of consecutive days off is as
+// large as possible. Weekends and public holidays are days off, while half
+// days cost half a vacation day. Days off that are next to the start or end
+// of the year are included in the blocks. If several plans give the same
+// number of days off, the plan that uses the fewest vacation days is chosen.
+func PlanVacation(cal Calendar, year, vacationDays int, opts PlanOptions) VacationPlan {
+	if vacationDays > maxVacationDays {
+		vacationDays = maxVacationDays
+	}
+	first := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	n := actualDays(first, time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)) + 1
+
+	// The cost of each day, in half vacation days, where days off cost nothing
+	cost := make([]int, n)
+	for i := range cost {
+		date := first.AddDate(0, 0, i)
+		if BusinessDay(cal, date) {
+			cost[i] = 2
+			if half, _ := HalfDay(cal, date); half {
+				cost[i] = 1
+			}
+		}
+	}
+
+	// The days off just before and after the year, that extend the blocks at the edges
+	offDays := func(date time.Time, step int) int {
+		count := 0
+		for ; count < maxSearchDays && !BusinessDay(cal, date); date = date.AddDate(0, 0, step) {
+			count++
+		}
+		return count
+	}
+	before, after := offDays(first.AddDate(0, 0, -1), -1), offDays(first.AddDate(0, 0, n), 1)
+
+	// A state is a day where a block may start, since the day before is not
+	// a day off, or the first day of the year. best[i][c] is the best result
+	// from the state i to the end of the year, with a budget of c half days.
+	type result struct {
+		value, used int
+		end         int // the last day of the block that starts at i, or -1 for no block
+		next        int // the next state
+	}
+	budget := 2 * vacationDays
+	best := make([][]result, n+2)
+	for i := range best {
+		best[i] = make([]result, budget+1)
+	}
+	better := func(a, b result) bool {
+		return a.value > b.value || (a.value == b.value && a.used < b.used)
+	}
+	for i := n - 1; i >= 0; i-- {
+		// Skip to the next business day, that is not taken as vacation
+		j := i
+		for j < n && cost[j] == 0 {
+			j++
+		}
+		for c := 0; c <= budget; c++ {
+			r := result{end: -1, next: n}
+			if j+1 < n {
+				r = best[j+1][c]
+				r.end, r.next = -1, j+1
+			}
+			// Or start a block at i, that ends on a day e before a business day
+			used := 0
+			for e := i; e < n && used <= c; e++ {
+				used += cost[e]
+				if used > c {
+					break
+				}
+				if used == 0 || (e+1 < n && cost[e+1] == 0) {
+					continue
+				}
+				value := e - i + 1
+				if i == 0 {
+					value += before
+				}
+				if e == n-1 {
+					value += after
+				}
+				if value < opts.MinBlock {
+					continue
+				}
+				candidate := result{value: value, used: used, end: e, next: n}
+				if e+2 < n {
+					rest := best[e+2][c-used]
+					candidate.value += rest.value
+					candidate.used += rest.used
+					candidate.next = e + 2
+				}
+				if better(candidate, r) {
+					r = candidate
+				}
+			}
+			best[i][c] = r
+		}
+	}
+
+	// Follow the choices from the first day of the year
+	var plan VacationPlan
+	for i, c := 0, budget; i < n; {
+		r := best[i][c]
+		if r.end < 0 {
+			i = r.next
+			continue
+		}
+		block := VacationBlock{
+			Start:   first.AddDate(0, 0, i),
+			End:     first.AddDate(0, 0, r.end),
+			DaysOff: r.end - i + 1,
+		}
+		if i == 0 {
+			block.Start = block.Start.AddDate(0, 0, -before)
+			block.DaysOff += before
+		}
+		if r.end == n-1 {
+			block.End = block.End.AddDate(0, 0, after)
+			block.DaysOff += after
+		}
+		for e := i; e <= r.end; e++ {
+			if cost[e] > 0 {
+				block.Leave = append(block.Leave, first.AddDate(0, 0, e))
+				block.VacationDays += float64(cost[e]) / 2
+				c -= cost[e]
+			}
+		}
+		plan.Blocks = append(plan.Blocks, block)
+		plan.DaysOff += block.DaysOff
+		plan.VacationDays += block.VacationDays
+		i = r.next
+	}
+	return plan
+}
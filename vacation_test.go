@@ -0,0 +1,48 @@
+package kal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBridgeDays(t *testing.T) {
+	days := BridgeDays(NewNorwegianCalendar(), 2025)
+	if len(days) != 2 || !days[0].Equal(date(2025, time.May, 2)) || !days[1].Equal(date(2025, time.May, 30)) {
+		t.Errorf("got %v, want 2 May and 30 May", days)
+	}
+}
+
+func TestPlanVacation(t *testing.T) {
+	cal := NewNorwegianCalendar()
+	p := PlanVacation(cal, 2025, 5, PlanOptions{})
+	if p.DaysOff != 25 || p.VacationDays != 5 || len(p.Blocks) != 6 {
+		t.Errorf("got %d days off for %g vacation days in %d blocks", p.DaysOff, p.VacationDays, len(p.Blocks))
+	}
+	for _, minBlock := range []int{0, 9} {
+		p = PlanVacation(cal, 2025, 10, PlanOptions{MinBlock: minBlock})
+		days := 0
+		for _, block := range p.Blocks {
+			if block.DaysOff < minBlock || actualDays(block.Start, block.End)+1 != block.DaysOff {
+				t.Errorf("unexpected block %+v", block)
+			}
+			for date := block.Start; !date.After(block.End); date = date.AddDate(0, 0, 1) {
+				if BusinessDay(cal, date) != containsDate(block.Leave, date) {
+					t.Errorf("%s is not a day off in %+v", date, block)
+				}
+			}
+			days += block.DaysOff
+		}
+		if days != p.DaysOff || p.VacationDays > 10 {
+			t.Errorf("got %d days off for %g vacation days", p.DaysOff, p.VacationDays)
+		}
+	}
+}
+
+func containsDate(dates []time.Time, date time.Time) bool {
+	for _, d := range dates {
+		if d.Equal(date) {
+			return true
+		}
+	}
+	return false
+}